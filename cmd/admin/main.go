@@ -0,0 +1,123 @@
+// Command threatdna-admin performs Kafka topic and partition administration
+// for a ThreatDNA deployment: creating/describing/deleting the CTI topic,
+// and submitting or inspecting KIP-455 partition reassignment plans so
+// operators can redistribute builder load without a cluster restart.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"threatdna/internal/threatdnacore"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+	cmdName := os.Args[1]
+
+	fs := flag.NewFlagSet(cmdName, flag.ExitOnError)
+	brokersFlag := fs.String("brokers", "localhost:9092", "comma-separated Kafka broker list")
+	topicFlag := fs.String("topic", "cti-records", "Kafka topic")
+	partitionsFlag := fs.Int("partitions", 3, "number of partitions for create-topic")
+	replicationFlag := fs.Int("replication-factor", 1, "replication factor for create-topic")
+	fs.Parse(os.Args[2:])
+
+	admin, err := threatdnacore.NewKafkaAdmin(strings.Split(*brokersFlag, ","))
+	if err != nil {
+		log.Fatalf("Failed to connect to Kafka: %v", err)
+	}
+	defer admin.Close()
+
+	switch cmdName {
+	case "create-topic":
+		if err := admin.CreateTopics(*topicFlag, int32(*partitionsFlag), int16(*replicationFlag)); err != nil {
+			log.Fatalf("Failed to create topic: %v", err)
+		}
+		log.Printf("✅ Topic %s ready (%d partitions, replication factor %d)", *topicFlag, *partitionsFlag, *replicationFlag)
+
+	case "describe-topic":
+		metadata, err := admin.DescribeTopics(*topicFlag)
+		if err != nil {
+			log.Fatalf("Failed to describe topic: %v", err)
+		}
+		printJSON(metadata)
+
+	case "delete-topic":
+		if err := admin.DeleteTopics(*topicFlag); err != nil {
+			log.Fatalf("Failed to delete topic: %v", err)
+		}
+		log.Printf("🗑️  Deleted topic %s", *topicFlag)
+
+	case "reassign":
+		runReassign(admin)
+
+	case "list-reassignments":
+		runListReassignments(admin, *topicFlag)
+
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println(`Usage: threatdna-admin <command> [flags]
+
+Commands:
+  create-topic         Create (or confirm) a topic
+  describe-topic       Describe a topic's partitions, replicas, and ISR
+  delete-topic         Delete a topic
+  reassign             Read a JSON plan {"topic": {"partition": [replica...]}} from stdin and submit it (KIP-455)
+  list-reassignments   Report in-progress partition reassignments for -topic`)
+}
+
+// runReassign reads {"<topic>": {"<partition>": [replica, ...]}} from stdin
+// and submits each topic's plan via AlterPartitionReassignments.
+func runReassign(admin *threatdnacore.KafkaAdmin) {
+	var raw map[string]map[string][]int32
+	if err := json.NewDecoder(os.Stdin).Decode(&raw); err != nil {
+		log.Fatalf("Failed to parse reassignment plan from stdin: %v", err)
+	}
+
+	for topic, partitions := range raw {
+		plan := make(map[int32][]int32, len(partitions))
+		for partitionStr, replicas := range partitions {
+			partition, err := strconv.ParseInt(partitionStr, 10, 32)
+			if err != nil {
+				log.Printf("⚠️  Skipping topic %s: invalid partition key %q: %v", topic, partitionStr, err)
+				continue
+			}
+			plan[int32(partition)] = replicas
+		}
+
+		if err := admin.AlterPartitionReassignments(topic, plan); err != nil {
+			log.Printf("⚠️  Failed to submit reassignment for topic %s: %v", topic, err)
+			continue
+		}
+		log.Printf("✅ Submitted reassignment plan for topic %s (%d partitions)", topic, len(plan))
+	}
+}
+
+func runListReassignments(admin *threatdnacore.KafkaAdmin, topic string) {
+	status, err := admin.ListPartitionReassignments(topic, nil)
+	if err != nil {
+		log.Fatalf("Failed to list reassignments: %v", err)
+	}
+	printJSON(status)
+}
+
+func printJSON(v interface{}) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal output: %v", err)
+	}
+	fmt.Println(string(out))
+}
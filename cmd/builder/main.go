@@ -2,15 +2,24 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"log"
+	"net/http"
 	"os"
 
 	"threatdna/internal/threatdnacore"
 )
 
 const dbPath = "threats.bleve/test_genomes.db"
+const statsListenPort = ":9090"
 
 func main() {
+	redisURLFlag := flag.String("redis-url", "", "optional Redis URL (e.g. redis://localhost:6379/0) for the hot-path genome/search cache")
+	batchFileFlag := flag.String("batch-file", "", "offline mode: build and index genomes from this CTI records JSON file, then exit, instead of consuming Kafka")
+	progressFlag := flag.Bool("progress", true, "-batch-file mode: show a terminal progress bar with throughput/ETA while building")
+	flag.Parse()
+
 	log.Println("Starting ThreatDNA Builder (Consumer & Indexer)")
 
 	kafkaBroker := os.Getenv("KAFKA_BROKER")
@@ -31,6 +40,85 @@ func main() {
 	}
 	defer builder.Close()
 
+	if *redisURLFlag != "" {
+		cache, err := threatdnacore.NewRedisCache(*redisURLFlag, 0)
+		if err != nil {
+			log.Fatalf("Failed to connect to redis at %s: %v", *redisURLFlag, err)
+		}
+		defer cache.Close()
+		builder.SetCache(cache)
+		log.Printf("Redis hot-path cache enabled at %s", *redisURLFlag)
+	}
+
+	if *batchFileFlag != "" {
+		records, err := loadCTIRecords(*batchFileFlag)
+		if err != nil {
+			log.Fatalf("Failed to load CTI records from %s: %v", *batchFileFlag, err)
+		}
+
+		var progress threatdnacore.Progress = threatdnacore.NoopProgress{}
+		if *progressFlag {
+			progress = threatdnacore.NewConsoleProgress()
+		}
+
+		genomes, err := builder.BuildGenomesFromRecords(records, progress)
+		if err != nil {
+			log.Fatalf("Batch genome build failed: %v", err)
+		}
+		log.Printf("Built and indexed %d genomes from %d CTI records in %s", len(genomes), len(records), *batchFileFlag)
+		return
+	}
+
+	go serveStats(builder)
+
 	ctx := context.Background()
 	builder.StartKafkaConsumer(ctx)
 }
+
+// serveStats runs the builder's operator-facing HTTP surface: /stats for
+// dashboards and /healthz for readiness probes. It returns only if the
+// HTTP server fails.
+func serveStats(builder *threatdnacore.GenomeBuilder) {
+	hm := http.NewServeMux()
+	hm.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+		stats, err := builder.GetGenomeStats()
+		if err != nil {
+			log.Printf("Failed to compute genome stats: %v", err)
+			http.Error(w, "Internal server error: could not compute stats", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(stats); err != nil {
+			log.Printf("Failed to encode genome stats: %v", err)
+		}
+	})
+	hm.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		health := builder.GetHealth()
+		w.Header().Set("Content-Type", "application/json")
+		if health.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(health); err != nil {
+			log.Printf("Failed to encode health status: %v", err)
+		}
+	})
+
+	log.Printf("Serving /stats and /healthz on %s", statsListenPort)
+	if err := http.ListenAndServe(statsListenPort, hm); err != nil {
+		log.Printf("Stats/health server exited: %v", err)
+	}
+}
+
+// loadCTIRecords reads a JSON array of threatdnacore.CTIRecord from path,
+// for -batch-file's offline backfill mode.
+func loadCTIRecords(path string) ([]threatdnacore.CTIRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []threatdnacore.CTIRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
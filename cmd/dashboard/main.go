@@ -0,0 +1,280 @@
+// Command threatdna-dashboard serves an HTML dashboard and JSON API over a
+// genome store: the same top-TTPs/tactics/actor/campaign/timeline
+// aggregates the original batch pipeline used to print to stdout, plus
+// per-genome drill-down pages and a Prometheus /metrics endpoint.
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"threatdna/internal/threatdnacore"
+)
+
+const genomeDBPath = "threats.bleve/test_genomes.db"
+const listenPort = ":8090"
+
+// topListLimit bounds how many actors/campaigns/timeline buckets the
+// dashboard tables render, for the same long-tail reason as
+// threatdnacore's topTTPLimit.
+const topListLimit = 15
+
+//go:embed web/templates/*.html.tmpl
+var templateFS embed.FS
+
+//go:embed web/static
+var staticFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"barWidth": barWidth,
+}).ParseFS(templateFS, "web/templates/*.html.tmpl"))
+
+// barWidth scales count against max onto a 0-100 percentage for the
+// dashboard's CSS bar charts, so the longest bar always fills the track.
+func barWidth(count, max int) int {
+	if max <= 0 {
+		return 0
+	}
+	return count * 100 / max
+}
+
+// pairCount is a generic name/count row, used for every "top N" table the
+// dashboard renders (actors, campaigns, timeline buckets).
+type pairCount struct {
+	Name  string
+	Count int
+}
+
+// topPairs sorts counts by count descending (ties broken by name) and
+// returns at most limit entries.
+func topPairs(counts map[string]int, limit int) []pairCount {
+	pairs := make([]pairCount, 0, len(counts))
+	for name, count := range counts {
+		pairs = append(pairs, pairCount{Name: name, Count: count})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Count != pairs[j].Count {
+			return pairs[i].Count > pairs[j].Count
+		}
+		return pairs[i].Name < pairs[j].Name
+	})
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	return pairs
+}
+
+// maxCount returns the largest Count in pairs, or 0 for an empty slice.
+func maxCount(pairs []pairCount) int {
+	max := 0
+	for _, p := range pairs {
+		if p.Count > max {
+			max = p.Count
+		}
+	}
+	return max
+}
+
+// dashboardPageData is the template data for "/".
+type dashboardPageData struct {
+	Stats          *threatdnacore.GenomeStats
+	TopActors      []pairCount
+	TopCampaigns   []pairCount
+	TopTactics     []pairCount
+	Timeline       []pairCount
+	Genomes        []*threatdnacore.Genome
+	MaxTTPCount    int
+	MaxTacticCount int
+}
+
+// genomePageData is the template data for "/genomes/:id".
+type genomePageData struct {
+	Genome   *threatdnacore.Genome
+	Severity string
+}
+
+func dashboardHandler(builder *threatdnacore.GenomeBuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		stats, err := builder.GetGenomeStats()
+		if err != nil {
+			log.Printf("Failed to compute genome stats: %v", err)
+			http.Error(w, "Internal server error: could not compute stats", http.StatusInternalServerError)
+			return
+		}
+
+		genomes, err := builder.ListGenomes("", "", 0)
+		if err != nil {
+			log.Printf("Failed to list genomes: %v", err)
+			http.Error(w, "Internal server error: could not list genomes", http.StatusInternalServerError)
+			return
+		}
+		sort.Slice(genomes, func(i, j int) bool { return genomes[i].LastSeen.After(genomes[j].LastSeen) })
+
+		tacticCounts := topPairs(stats.ByTactic, topListLimit)
+		topTTPCount := 0
+		for _, ttp := range stats.TopTTPs {
+			if ttp.Count > topTTPCount {
+				topTTPCount = ttp.Count
+			}
+		}
+
+		timeline := make(map[string]int, len(stats.LastSeenBuckets))
+		for month, count := range stats.LastSeenBuckets {
+			timeline[month] += count
+		}
+
+		data := dashboardPageData{
+			Stats:          stats,
+			TopActors:      topPairs(stats.ByActor, topListLimit),
+			TopCampaigns:   topPairs(stats.ByCampaign, topListLimit),
+			TopTactics:     tacticCounts,
+			Timeline:       topPairs(timeline, topListLimit),
+			Genomes:        genomes,
+			MaxTTPCount:    topTTPCount,
+			MaxTacticCount: maxCount(tacticCounts),
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := templates.ExecuteTemplate(w, "dashboard.html.tmpl", data); err != nil {
+			log.Printf("Failed to render dashboard: %v", err)
+		}
+	}
+}
+
+func genomeHandler(builder *threatdnacore.GenomeBuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/genomes/")
+		if id == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		genome, err := builder.GetGenome(id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Genome %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		_, bucket := threatdnacore.GenomeSeverity(genome)
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := templates.ExecuteTemplate(w, "genome.html.tmpl", genomePageData{Genome: genome, Severity: bucket}); err != nil {
+			log.Printf("Failed to render genome %s: %v", id, err)
+		}
+	}
+}
+
+func apiStatsHandler(builder *threatdnacore.GenomeBuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats, err := builder.GetGenomeStats()
+		if err != nil {
+			http.Error(w, "Internal server error: could not compute stats", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+	}
+}
+
+func apiGenomesHandler(builder *threatdnacore.GenomeBuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor := r.URL.Query().Get("actor")
+		platform := r.URL.Query().Get("platform")
+		genomes, err := builder.ListGenomes(actor, platform, 0)
+		if err != nil {
+			http.Error(w, "Internal server error: could not list genomes", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(genomes)
+	}
+}
+
+func apiGenomeHandler(builder *threatdnacore.GenomeBuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/genomes/")
+		genome, err := builder.GetGenome(id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Genome %s not found", id), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(genome)
+	}
+}
+
+// metricsHandler publishes a small set of Prometheus text-exposition-format
+// gauges so ThreatDNA can be scraped like any other service, without
+// pulling in the official client library for three counters.
+func metricsHandler(builder *threatdnacore.GenomeBuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats, err := builder.GetGenomeStats()
+		if err != nil {
+			http.Error(w, "Internal server error: could not compute stats", http.StatusInternalServerError)
+			return
+		}
+		indexSize, err := builder.IndexSize()
+		if err != nil {
+			http.Error(w, "Internal server error: could not read index size", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		fmt.Fprintln(w, "# HELP threatdna_genomes_total Total genomes in the store.")
+		fmt.Fprintln(w, "# TYPE threatdna_genomes_total gauge")
+		fmt.Fprintf(w, "threatdna_genomes_total %d\n", stats.TotalGenomes)
+
+		fmt.Fprintln(w, "# HELP threatdna_index_documents Documents currently stored in the Bleve index.")
+		fmt.Fprintln(w, "# TYPE threatdna_index_documents gauge")
+		fmt.Fprintf(w, "threatdna_index_documents %d\n", indexSize)
+
+		fmt.Fprintln(w, "# HELP threatdna_genomes_by_actor Genome count per actor.")
+		fmt.Fprintln(w, "# TYPE threatdna_genomes_by_actor gauge")
+		for actor, count := range stats.ByActor {
+			fmt.Fprintf(w, "threatdna_genomes_by_actor{actor=%q} %d\n", actor, count)
+		}
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	builder, err := threatdnacore.NewGenomeBuilder(genomeDBPath, "", "")
+	if err != nil {
+		log.Fatalf("Failed to open genome store: %v", err)
+	}
+	defer builder.Close()
+
+	staticSub, err := fs.Sub(staticFS, "web/static")
+	if err != nil {
+		log.Fatalf("Failed to mount embedded static assets: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.FS(staticSub))))
+	mux.HandleFunc("/", dashboardHandler(builder))
+	mux.HandleFunc("/genomes/", genomeHandler(builder))
+	mux.HandleFunc("/api/stats", apiStatsHandler(builder))
+	mux.HandleFunc("/api/genomes", apiGenomesHandler(builder))
+	mux.HandleFunc("/api/genomes/", apiGenomeHandler(builder))
+	mux.HandleFunc("/metrics", metricsHandler(builder))
+
+	log.Printf("Serving ThreatDNA dashboard on %s", listenPort)
+	if err := http.ListenAndServe(listenPort, mux); err != nil {
+		log.Fatalf("Dashboard server exited: %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+// Command threatdna-fetch-cve populates the local NVD feed and CISA KEV
+// catalog files threatdnacore.CVEEnricher reads, the same way the MITRE
+// ATT&CK bundle is pulled down once and then loaded from disk on every run.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const kevFeedURL = "https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json"
+
+// nvdVulnerability mirrors the subset of the NVD 2.0 JSON API response
+// CVEEnricher's feed files need.
+type nvdVulnerability struct {
+	CVE struct {
+		ID          string `json:"id"`
+		Published   string `json:"published"`
+		Descriptions []struct {
+			Lang  string `json:"lang"`
+			Value string `json:"value"`
+		} `json:"descriptions"`
+		References []struct {
+			URL string `json:"url"`
+		} `json:"references"`
+		Metrics struct {
+			CVSSMetricV31 []struct {
+				CVSSData struct {
+					BaseScore    float64 `json:"baseScore"`
+					VectorString string  `json:"vectorString"`
+				} `json:"cvssData"`
+			} `json:"cvssMetricV31"`
+		} `json:"metrics"`
+		Weaknesses []struct {
+			Description []struct {
+				Value string `json:"value"`
+			} `json:"description"`
+		} `json:"weaknesses"`
+	} `json:"cve"`
+}
+
+type nvdCVEResponse struct {
+	Vulnerabilities []nvdVulnerability `json:"vulnerabilities"`
+}
+
+// feedEntry mirrors threatdnacore's unexported nvdFeedEntry, which this
+// command has no import access to, so the field set is kept in lockstep by
+// hand.
+type feedEntry struct {
+	ID          string    `json:"id"`
+	CVSSScore   float64   `json:"cvss_score"`
+	CVSSVector  string    `json:"cvss_vector"`
+	CWE         string    `json:"cwe"`
+	Vendor      string    `json:"vendor"`
+	Product     string    `json:"product"`
+	Versions    []string  `json:"versions"`
+	Published   time.Time `json:"published"`
+	Description string    `json:"description"`
+	References  []string  `json:"references"`
+}
+
+func main() {
+	feedDirFlag := flag.String("feed-dir", "data/nvd", "directory to write fetched NVD feed entries into")
+	kevPathFlag := flag.String("kev-path", "data/nvd/kev.json", "path to write the fetched CISA KEV catalog to")
+	cveIDsFlag := flag.String("cve-ids", "", "comma-separated CVE IDs to fetch from NVD (e.g. CVE-2024-12345,CVE-2023-0001)")
+	apiKeyFlag := flag.String("api-key", os.Getenv("NVD_API_KEY"), "NVD API key (optional, raises the rate limit)")
+	flag.Parse()
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	if err := fetchKEV(client, *kevPathFlag); err != nil {
+		log.Fatalf("Failed to fetch CISA KEV catalog: %v", err)
+	}
+	log.Printf("✅ CISA KEV catalog written to %s", *kevPathFlag)
+
+	if *cveIDsFlag == "" {
+		log.Printf("No -cve-ids given, skipping NVD fetch")
+		return
+	}
+
+	cveIDs := strings.Split(*cveIDsFlag, ",")
+	entries := make([]feedEntry, 0, len(cveIDs))
+	for _, cveID := range cveIDs {
+		cveID = strings.TrimSpace(cveID)
+		if cveID == "" {
+			continue
+		}
+		entry, err := fetchNVDEntry(client, *apiKeyFlag, cveID)
+		if err != nil {
+			log.Printf("⚠️  Skipping %s: %v", cveID, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := writeFeedFile(*feedDirFlag, entries); err != nil {
+		log.Fatalf("Failed to write NVD feed file: %v", err)
+	}
+	log.Printf("✅ Fetched %d/%d CVEs into %s", len(entries), len(cveIDs), *feedDirFlag)
+}
+
+// fetchKEV downloads the CISA KEV catalog verbatim, since its JSON shape
+// already matches what CVEEnricher's loadKEVCatalog expects.
+func fetchKEV(client *http.Client, kevPath string) error {
+	resp, err := client.Get(kevFeedURL)
+	if err != nil {
+		return fmt.Errorf("failed to download CISA KEV catalog: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("CISA KEV feed returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read CISA KEV response: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(kevPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(kevPath), err)
+	}
+	return os.WriteFile(kevPath, body, 0644)
+}
+
+// fetchNVDEntry queries the NVD 2.0 JSON API for a single CVE ID and
+// converts the response into the feed entry shape CVEEnricher reads back.
+func fetchNVDEntry(client *http.Client, apiKey, cveID string) (feedEntry, error) {
+	url := fmt.Sprintf("https://services.nvd.nist.gov/rest/json/cves/2.0?cveId=%s", cveID)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return feedEntry{}, fmt.Errorf("failed to build NVD request: %w", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("apiKey", apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return feedEntry{}, fmt.Errorf("failed to query NVD: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return feedEntry{}, fmt.Errorf("NVD returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return feedEntry{}, fmt.Errorf("failed to read NVD response: %w", err)
+	}
+
+	var parsed nvdCVEResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return feedEntry{}, fmt.Errorf("failed to parse NVD response: %w", err)
+	}
+	if len(parsed.Vulnerabilities) == 0 {
+		return feedEntry{}, fmt.Errorf("NVD has no record of %s", cveID)
+	}
+
+	return feedEntryFromNVD(cveID, parsed.Vulnerabilities[0]), nil
+}
+
+func feedEntryFromNVD(cveID string, vuln nvdVulnerability) feedEntry {
+	entry := feedEntry{ID: cveID}
+
+	if metrics := vuln.CVE.Metrics.CVSSMetricV31; len(metrics) > 0 {
+		entry.CVSSScore = metrics[0].CVSSData.BaseScore
+		entry.CVSSVector = metrics[0].CVSSData.VectorString
+	}
+
+	if len(vuln.CVE.Weaknesses) > 0 && len(vuln.CVE.Weaknesses[0].Description) > 0 {
+		entry.CWE = vuln.CVE.Weaknesses[0].Description[0].Value
+	}
+
+	for _, d := range vuln.CVE.Descriptions {
+		if d.Lang == "en" {
+			entry.Description = d.Value
+			break
+		}
+	}
+
+	for _, ref := range vuln.CVE.References {
+		entry.References = append(entry.References, ref.URL)
+	}
+
+	if t, err := time.Parse(time.RFC3339, vuln.CVE.Published); err == nil {
+		entry.Published = t
+	}
+
+	return entry
+}
+
+// writeFeedFile writes entries as a single NVD feed page under feedDir,
+// named so it sorts after any previously-fetched pages.
+func writeFeedFile(feedDir string, entries []feedEntry) error {
+	if err := os.MkdirAll(feedDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", feedDir, err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal feed entries: %w", err)
+	}
+
+	path := filepath.Join(feedDir, fmt.Sprintf("fetched_%d.json", time.Now().Unix()))
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,176 @@
+// Command threatdna-genomes lists genomes and prints collection statistics
+// from an already-built genome store in a scriptable format, replacing the
+// emoji-decorated log output the original batch builder printed to stdout.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"threatdna/internal/threatdnacore"
+	"threatdna/internal/threatdnacore/render"
+)
+
+const genomeDBPath = "threats.bleve/test_genomes.db"
+const matrixOutDir = "threats.bleve/matrices"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+	cmdName := os.Args[1]
+
+	fs := flag.NewFlagSet(cmdName, flag.ExitOnError)
+	outputFlag := fs.String("output", "human", "output format: human, table, csv, or json")
+	machineFlag := fs.Bool("machine", false, "in table/csv mode, add source-count and IOC-count columns")
+	actorFlag := fs.String("actor", "", "list: filter by actor name substring")
+	platformFlag := fs.String("platform", "", "list: filter by platform substring")
+	limitFlag := fs.Int("limit", 0, "list: maximum genomes to return (0 = unlimited)")
+	tacticFlag := fs.String("tactic", "", "sliceGenomes: comma-separated tactics to restrict matrix columns to (e.g. execution,persistence)")
+	actorRegexFlag := fs.String("actor-regex", "", "sliceGenomes: only include genomes whose actor matches this regexp")
+	minConfidenceFlag := fs.Float64("min-confidence", 0, "sliceGenomes: minimum genome confidence")
+	sinceFlag := fs.String("since", "", "sliceGenomes: only genomes last seen on or after this date (YYYY-MM-DD)")
+	untilFlag := fs.String("until", "", "sliceGenomes: only genomes first seen on or before this date (YYYY-MM-DD)")
+	shardsFlag := fs.Int("shards", 1, "sliceGenomes: number of output shards, hashed by blake2b(genome ID) mod shards")
+	outDirFlag := fs.String("out-dir", matrixOutDir, "sliceGenomes: directory to write .npy/.manifest.json shard files to")
+	prefixFlag := fs.String("prefix", "genome_matrix", "sliceGenomes: filename prefix for shard files")
+	stixFileFlag := fs.String("stix-file", "", "exportSTIX/importSTIX: file path to write/read the STIX bundle (default stdout/stdin)")
+	fs.Parse(os.Args[2:])
+
+	format, err := render.ParseFormat(*outputFlag)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	builder, err := threatdnacore.NewGenomeBuilder(genomeDBPath, "", "")
+	if err != nil {
+		log.Fatalf("Failed to open genome store: %v", err)
+	}
+	defer builder.Close()
+
+	switch cmdName {
+	case "list":
+		genomes, err := builder.ListGenomes(*actorFlag, *platformFlag, *limitFlag)
+		if err != nil {
+			log.Fatalf("Failed to list genomes: %v", err)
+		}
+		data, err := render.RenderGenomes(genomes, format, *machineFlag)
+		if err != nil {
+			log.Fatalf("Failed to render genomes: %v", err)
+		}
+		os.Stdout.Write(data)
+
+	case "stats":
+		stats, err := builder.GetGenomeStats()
+		if err != nil {
+			log.Fatalf("Failed to compute genome stats: %v", err)
+		}
+		data, err := render.RenderStats(stats, format)
+		if err != nil {
+			log.Fatalf("Failed to render genome stats: %v", err)
+		}
+		os.Stdout.Write(data)
+
+	case "sliceGenomes":
+		filter := threatdnacore.MatrixFilter{
+			ActorRegex:    *actorRegexFlag,
+			MinConfidence: *minConfidenceFlag,
+		}
+		if *tacticFlag != "" {
+			filter.Tactics = strings.Split(*tacticFlag, ",")
+		}
+		if *sinceFlag != "" {
+			t, err := time.Parse("2006-01-02", *sinceFlag)
+			if err != nil {
+				log.Fatalf("Invalid -since %q: %v", *sinceFlag, err)
+			}
+			filter.Since = t
+		}
+		if *untilFlag != "" {
+			t, err := time.Parse("2006-01-02", *untilFlag)
+			if err != nil {
+				log.Fatalf("Invalid -until %q: %v", *untilFlag, err)
+			}
+			filter.Until = t
+		}
+
+		written, err := builder.ExportMatrix(filter, *shardsFlag, *outDirFlag, *prefixFlag)
+		if err != nil {
+			log.Fatalf("Failed to export genome matrix: %v", err)
+		}
+		for _, path := range written {
+			fmt.Println(path)
+		}
+
+	case "exportSTIX":
+		genomes, err := builder.ListGenomes(*actorFlag, *platformFlag, *limitFlag)
+		if err != nil {
+			log.Fatalf("Failed to list genomes: %v", err)
+		}
+
+		out := io.Writer(os.Stdout)
+		if *stixFileFlag != "" {
+			f, err := os.Create(*stixFileFlag)
+			if err != nil {
+				log.Fatalf("Failed to create %s: %v", *stixFileFlag, err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if err := builder.ExportSTIX(out, genomes); err != nil {
+			log.Fatalf("Failed to export STIX bundle: %v", err)
+		}
+
+	case "importSTIX":
+		in := io.Reader(os.Stdin)
+		if *stixFileFlag != "" {
+			f, err := os.Open(*stixFileFlag)
+			if err != nil {
+				log.Fatalf("Failed to open %s: %v", *stixFileFlag, err)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		genomes, err := builder.ImportSTIX(in)
+		if err != nil {
+			log.Fatalf("Failed to import STIX bundle: %v", err)
+		}
+		log.Printf("Built and indexed %d genomes from STIX bundle", len(genomes))
+
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println(`Usage: threatdna-genomes <command> [flags]
+
+Commands:
+  list           List genomes, optionally filtered by -actor/-platform/-limit
+  stats          Print genome collection statistics
+  sliceGenomes   Export a genomes x techniques matrix (.npy + manifest.json) for ML tooling
+  exportSTIX     Export genomes as a STIX 2.1 bundle, optionally filtered by -actor/-platform/-limit
+  importSTIX     Build and index genomes from a STIX 2.1 bundle (OpenCTI, MISP, TAXII 2.1)
+
+Flags:
+  -output string          output format: human, table, csv, or json (default "human") (list/stats only)
+  -machine                in table/csv mode, add source-count and IOC-count columns (list only)
+  -tactic string          comma-separated tactics to restrict matrix columns to (sliceGenomes only)
+  -actor-regex string     only include genomes whose actor matches this regexp (sliceGenomes only)
+  -min-confidence float   minimum genome confidence (sliceGenomes only)
+  -since string           only genomes last seen on or after this date (sliceGenomes only)
+  -until string           only genomes first seen on or before this date (sliceGenomes only)
+  -shards int             number of output shards (sliceGenomes only, default 1)
+  -out-dir string         output directory for shard files (sliceGenomes only)
+  -prefix string          filename prefix for shard files (sliceGenomes only)
+  -stix-file string       file to write/read the STIX bundle (exportSTIX/importSTIX, default stdout/stdin)`)
+}
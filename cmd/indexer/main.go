@@ -6,35 +6,41 @@ import (
 	"os"
 	"time"
 
-	"github.com/blevesearch/bleve/v2"
 	"threatdna/internal/threatdnacore"
+	"threatdna/internal/threatdnacore/search"
 )
 
 func main() {
 	// --- 1. Define and Parse Command-Line Flags ---
+	backendFlag := flag.String("backend", "bleve", "search backend to index into: bleve or elastic")
+	urlFlag := flag.String("url", "", "connection URL for the elastic backend (e.g. http://localhost:9200); ignored for bleve")
+	batchSizeFlag := flag.Int("elastic-batch-size", 0, "elastic backend bulk request batch size (0 = default)")
+	flushIntervalFlag := flag.Duration("elastic-flush-interval", 0, "elastic backend periodic flush interval (0 = default)")
+	indexBatchSizeFlag := flag.Int("batch-size", 0, "bleve backend: documents per flushed batch (0 = default)")
+	progressFlag := flag.Bool("progress", true, "bleve backend: show a terminal progress bar with throughput/ETA while indexing")
 	flag.Parse()
 
 	indexPath := "threats.bleve/bleve_index"
 
-	var index bleve.Index
-
-	// --- 2. Handle Existing Index / Create New ---
-	if _, err := os.Stat(indexPath); err == nil {
-		// Index path exists, try to open it
-		log.Printf("Index '%s' already exists. Opening existing index...", indexPath)
-		index, err = bleve.Open(indexPath)
-		if err != nil {
-			log.Fatalf("Failed to open existing Bleve index: %v", err)
+	var backend search.Backend
+	switch *backendFlag {
+	case "bleve":
+		backend = search.NewBleveBackend()
+	case "elastic":
+		if *urlFlag == "" {
+			log.Fatalf("-backend=elastic requires -url")
 		}
-	} else if os.IsNotExist(err) {
-		// Index path does not exist, create a new one
-		log.Printf("Creating new Bleve index at '%s'...", indexPath)
-		index = threatdnacore.CreateBleveIndex(indexPath)
-	} else {
-		// Some other error occurred trying to stat the path
-		log.Fatalf("Error checking index path '%s': %v", indexPath, err)
+		backend = search.NewElasticBackend(*urlFlag, *batchSizeFlag, *flushIntervalFlag)
+		indexPath = "threatdna_genomes"
+	default:
+		log.Fatalf("Unknown -backend %q: must be bleve or elastic", *backendFlag)
+	}
+
+	log.Printf("Opening %s index at %q...", *backendFlag, indexPath)
+	if err := backend.CreateIndex(indexPath); err != nil {
+		log.Fatalf("Failed to open %s index: %v", *backendFlag, err)
 	}
-	defer index.Close()
+	defer backend.Close()
 
 	// --- 3. Load Source Data from BoltDB ---
 	dbPath := os.Getenv("DB_PATH")
@@ -76,7 +82,32 @@ func main() {
 
 	// --- 5. Index the Data ---
 	log.Println("Indexing documents...")
-	threatdnacore.IndexBleveData(index, genomes)
+	ctiMap := map[string]string{} // BoltDB genomes don't carry raw source text; see BuildSearchDocument.
+
+	if bleveBackend, ok := backend.(*search.BleveBackend); ok {
+		// Bypass the generic Backend.IndexBatch path for bleve so we get
+		// batched flushing and progress reporting from IndexBleveData;
+		// ElasticBackend already flushes/batches on its own below.
+		valueGenomes := make([]threatdnacore.Genome, len(genomes))
+		for i, genome := range genomes {
+			valueGenomes[i] = *genome
+		}
+
+		var progress threatdnacore.Progress = threatdnacore.NoopProgress{}
+		if *progressFlag {
+			progress = threatdnacore.NewConsoleProgress()
+		}
+		threatdnacore.IndexBleveData(bleveBackend.Index(), valueGenomes, ctiMap, *indexBatchSizeFlag, progress)
+	} else {
+		docs := make(map[string]threatdnacore.SearchDocument, len(genomes))
+		for _, genome := range genomes {
+			docs[genome.ID] = threatdnacore.BuildSearchDocument(*genome, ctiMap)
+		}
+		if err := backend.IndexBatch(docs); err != nil {
+			log.Fatalf("Failed to index documents into %s backend: %v", *backendFlag, err)
+		}
+		log.Printf("Successfully indexed %d documents.", len(docs))
+	}
 
 	log.Println("Indexing complete.")
 }
\ No newline at end of file
@@ -2,23 +2,37 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
 	"os"
-	"time"
+	"os/signal"
+	"strconv"
+	"strings"
 
 	"github.com/segmentio/kafka-go"
 
+	"threatdna/internal/kafkaio"
 	"threatdna/internal/threatdnacore"
 )
 
 func main() {
 	log.Println("Starting ThreatDNA Producer")
 
-	kafkaBroker := os.Getenv("KAFKA_BROKER")
-	if kafkaBroker == "" {
-		kafkaBroker = "localhost:9092"
-		log.Printf("KAFKA_BROKER environment variable not set, using default: %s", kafkaBroker)
+	watch := flag.Bool("watch", false, "run as a long-lived agent, tailing --watch-config for new/appended reports instead of a one-shot batch publish")
+	watchConfigPath := flag.String("watch-config", "config/watch.yaml", "path to the watch-mode YAML config (paths, globs, poll interval, workers)")
+	dryRun := flag.Bool("dry-run", false, "run the full ingest+serialize path without contacting Kafka, to benchmark or smoke-test the pipeline")
+	workers := flag.Int("publish-workers", kafkaio.DefaultBatchConfig().Workers, "number of goroutines publishing concurrently (kafka sink)")
+	batchSize := flag.Int("batch-size", kafkaio.DefaultBatchConfig().BatchSize, "kafka writer batch size (messages)")
+	batchBytes := flag.Int64("batch-bytes", kafkaio.DefaultBatchConfig().BatchBytes, "kafka writer batch size (bytes)")
+	batchTimeout := flag.Duration("batch-timeout", kafkaio.DefaultBatchConfig().BatchTimeout, "kafka writer max delay before flushing a batch")
+	flag.Parse()
+
+	batchCfg := kafkaio.BatchConfig{
+		Workers:      *workers,
+		BatchSize:    *batchSize,
+		BatchBytes:   *batchBytes,
+		BatchTimeout: *batchTimeout,
 	}
 
 	kafkaTopic := os.Getenv("KAFKA_TOPIC")
@@ -27,20 +41,250 @@ func main() {
 		log.Printf("KAFKA_TOPIC environment variable not set, using default: %s", kafkaTopic)
 	}
 
+	sinks, closeSinks := buildSinks(*dryRun, batchCfg, kafkaTopic)
+	defer closeSinks()
+
+	if *watch {
+		runWatchMode(sinks, *watchConfigPath)
+		return
+	}
+
+	log.Println("Publishing initial data to configured sinks...")
+	publishInitialData(sinks)
+}
+
+// buildSinks assembles the RecordSinks the producer fans every ingested
+// record out to, selected by the comma-separated SINKS env var (default
+// "kafka") so operators who don't want to run Kafka can still consume
+// ThreatDNA's output by pointing SINKS at "elastic" alone, or both.
+// --dry-run bypasses sink construction entirely in favor of a sink that
+// only logs.
+func buildSinks(dryRun bool, batchCfg kafkaio.BatchConfig, topic string) (sinks []threatdnacore.RecordSink, closeFn func()) {
+	if dryRun {
+		log.Println("🧪 --dry-run set: ingesting and serializing records without contacting any sink")
+		return []threatdnacore.RecordSink{dryRunSink{}}, func() {}
+	}
+
+	names := strings.Split(envOr("SINKS", "kafka"), ",")
+	var closers []func()
+	for _, name := range names {
+		switch strings.TrimSpace(name) {
+		case "kafka":
+			sink, closer := newKafkaSink(batchCfg, topic)
+			sinks = append(sinks, sink)
+			closers = append(closers, closer)
+		case "elastic":
+			url := envOr("ELASTIC_URL", "http://localhost:9200")
+			indexBase := envOr("ELASTIC_INDEX", "cti-records")
+			alias := os.Getenv("ELASTIC_ALIAS")
+			sinks = append(sinks, threatdnacore.NewElasticSink(url, indexBase, alias))
+		case "":
+			// tolerate a trailing comma in SINKS
+		default:
+			log.Printf("⚠️  Unknown sink %q in SINKS, skipping", name)
+		}
+	}
+
+	return sinks, func() {
+		for _, closer := range closers {
+			closer()
+		}
+	}
+}
+
+// batchKafkaSink adapts a kafkaio.BatchPublisher (DLQ-backed, concurrent)
+// to the RecordSink interface, so Kafka participates in sink fan-out the
+// same way ElasticSink does.
+type batchKafkaSink struct {
+	bp    *kafkaio.BatchPublisher
+	topic string
+	codec threatdnacore.RecordCodec
+}
+
+func newKafkaSink(batchCfg kafkaio.BatchConfig, topic string) (threatdnacore.RecordSink, func()) {
+	kafkaBroker := os.Getenv("KAFKA_BROKER")
+	if kafkaBroker == "" {
+		kafkaBroker = "localhost:9092"
+		log.Printf("KAFKA_BROKER environment variable not set, using default: %s", kafkaBroker)
+	}
+
+	codec, err := threatdnacore.NewRecordCodec(valueFormatFor(topic))
+	if err != nil {
+		log.Fatalf("Failed to resolve value codec for topic %s: %v", topic, err)
+	}
+
 	writer := &kafka.Writer{
-		Addr:     kafka.TCP(kafkaBroker),
-		Topic:    kafkaTopic,
-		Balancer: &kafka.LeastBytes{},
-		BatchTimeout: 10 * time.Millisecond,
+		Addr:         kafka.TCP(kafkaBroker),
+		Topic:        topic,
+		Balancer:     &kafka.LeastBytes{},
+		BatchSize:    batchCfg.BatchSize,
+		BatchBytes:   batchCfg.BatchBytes,
+		BatchTimeout: batchCfg.BatchTimeout,
 		RequiredAcks: kafka.RequireOne,
 	}
-	defer writer.Close()
+	producer := kafkaio.NewProducer(writer, buildInterceptors()...)
+	dlq := threatdnacore.NewDLQPublisher(producer)
+	bp := kafkaio.NewBatchPublisher(context.Background(), dlq.PublishWithDLQ, batchCfg)
+
+	sink := &batchKafkaSink{bp: bp, topic: topic, codec: codec}
+	return sink, func() {
+		bp.Close()
+		producer.Close()
+	}
+}
+
+// valueFormatFor resolves the wire format NewRecordCodec should use for
+// topic: a per-topic KAFKA_VALUE_FORMAT_<TOPIC> override (topic
+// upper-cased, non-alphanumerics turned to underscores) takes precedence
+// over the blanket KAFKA_VALUE_FORMAT, so a deployment publishing to both
+// a SIEM-facing and an analytics-facing topic can pick CEF for one and
+// JSON for the other without two producer processes.
+func valueFormatFor(topic string) string {
+	key := "KAFKA_VALUE_FORMAT_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - ('a' - 'A')
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, topic)
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return os.Getenv("KAFKA_VALUE_FORMAT")
+}
+
+func (s *batchKafkaSink) Publish(ctx context.Context, records []threatdnacore.CTIRecord) error {
+	for _, record := range records {
+		data, err := s.codec.Encode(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode record %s for kafka sink: %w", record.ID, err)
+		}
+		s.bp.Publish(&kafkaio.Record{
+			Topic:   s.topic,
+			Key:     []byte(record.ID),
+			Value:   data,
+			Headers: map[string]string{"content-type": s.codec.ContentType()},
+		})
+	}
+
+	var firstErr error
+	for range records {
+		if report := <-s.bp.Reports(); report.Err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("kafka sink failed to publish record %s: %w", string(report.Record.Key), report.Err)
+		}
+	}
+	return firstErr
+}
+
+func (s *batchKafkaSink) Close() error {
+	return nil
+}
+
+// dryRunSink stands in for every configured sink under --dry-run: it logs
+// what would have been published without touching the network.
+type dryRunSink struct{}
+
+func (dryRunSink) Publish(ctx context.Context, records []threatdnacore.CTIRecord) error {
+	for _, record := range records {
+		log.Printf("🧪 [dry-run] would publish record %s", record.ID)
+	}
+	return nil
+}
+
+func (dryRunSink) Close() error { return nil }
 
-	log.Println("Publishing initial data to Kafka...")
-	publishInitialData(writer, kafkaTopic)
+// buildInterceptors assembles the Kafka sink's Producer interceptor chain
+// from env vars, so deployments opt into redaction/metrics/tracing/
+// schema-registry serialization without a code change. Order matters:
+// redaction must see the plain JSON record, so it runs before the Avro
+// serializer rewrites Value into the registry's wire format.
+func buildInterceptors() []kafkaio.ProducerInterceptor {
+	var chain []kafkaio.ProducerInterceptor
+
+	if envBool("KAFKA_REDACT_ENABLED", true) {
+		chain = append(chain, kafkaio.NewRedactionInterceptor())
+	}
+	if envBool("KAFKA_METRICS_ENABLED", true) {
+		chain = append(chain, kafkaio.NewMetricsInterceptor())
+	}
+	if envBool("KAFKA_TRACING_ENABLED", false) {
+		chain = append(chain, kafkaio.NewTracingInterceptor())
+	}
+	if registryURL := os.Getenv("KAFKA_SCHEMA_REGISTRY_URL"); registryURL != "" {
+		subject := os.Getenv("KAFKA_AVRO_SUBJECT")
+		if subject == "" {
+			subject = "cti-records-value"
+		}
+		chain = append(chain, kafkaio.NewAvroSerializer(registryURL, subject))
+	}
+
+	return chain
+}
+
+func envBool(name string, def bool) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("⚠️  Invalid boolean for %s=%q, using default %v", name, v, def)
+		return def
+	}
+	return b
 }
 
-func publishInitialData(writer *kafka.Writer, topic string) {
+func envOr(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// runWatchMode runs the producer as a long-lived agent: it monitors the
+// directories/globs named in the watch config for new or appended reports
+// and fans each one out to every configured sink as it's discovered,
+// until interrupted.
+func runWatchMode(sinks []threatdnacore.RecordSink, configPath string) {
+	cfg, err := threatdnacore.LoadWatchConfig(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load watch config %s: %v", configPath, err)
+	}
+
+	ingester := threatdnacore.NewDataIngester()
+	if err := ingester.Initialize(); err != nil {
+		log.Fatalf("Failed to initialize data ingester: %v", err)
+	}
+
+	state, err := threatdnacore.LoadWatchState(cfg.StateFile)
+	if err != nil {
+		log.Fatalf("Failed to load watch state %s: %v", cfg.StateFile, err)
+	}
+
+	publish := func(record *threatdnacore.CTIRecord) error {
+		return publishToSinks(sinks, []threatdnacore.CTIRecord{*record})
+	}
+
+	watcher := threatdnacore.NewWatcher(cfg, ingester, state, publish)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		<-sigCh
+		log.Println("🛑 Shutting down watcher...")
+		watcher.Close()
+	}()
+
+	log.Printf("👀 Watching %v (tailing %v) every %s with %d workers...", cfg.Paths, cfg.TailGlobs, cfg.PollInterval, cfg.Workers)
+	if err := watcher.Run(); err != nil {
+		log.Fatalf("Watcher exited with error: %v", err)
+	}
+}
+
+func publishInitialData(sinks []threatdnacore.RecordSink) {
 	// Initialize and process data directory
 	ingester := threatdnacore.NewDataIngester()
 	if err := ingester.Initialize(); err != nil {
@@ -50,22 +294,24 @@ func publishInitialData(writer *kafka.Writer, topic string) {
 	records, err := ingester.IngestDirectory("data")
 	if err != nil {
 		log.Printf("❌ Error processing data directory: %v", err)
-	} else {
-		log.Printf("🎉 Ingested %d records from data directory. Publishing to Kafka...", len(records))
-		for _, record := range records {
-			data, err := json.Marshal(record)
-			if err != nil {
-				log.Printf("❌ Error marshaling record %s: %v", record.ID, err)
-				continue
-			}
-			msg := kafka.Message{
-				Key:   []byte(record.ID),
-				Value: data,
-			}
-			if err := writer.WriteMessages(context.Background(), msg); err != nil {
-				log.Printf("❌ Error publishing record %s to Kafka: %v", record.ID, err)
-			}
+		return
+	}
+
+	log.Printf("🎉 Ingested %d records from data directory. Publishing to %d sink(s)...", len(records), len(sinks))
+	if err := publishToSinks(sinks, records); err != nil {
+		log.Printf("❌ Error publishing records: %v", err)
+	}
+	log.Println("✅ Finished publishing records from data directory.")
+}
+
+// publishToSinks fans records out to every sink, continuing on to the
+// remaining sinks even if one fails, and returns the first error seen.
+func publishToSinks(sinks []threatdnacore.RecordSink, records []threatdnacore.CTIRecord) error {
+	var firstErr error
+	for _, sink := range sinks {
+		if err := sink.Publish(context.Background(), records); err != nil && firstErr == nil {
+			firstErr = err
 		}
-		log.Println("✅ Finished publishing records from data directory.")
 	}
-}
\ No newline at end of file
+	return firstErr
+}
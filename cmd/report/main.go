@@ -0,0 +1,93 @@
+// Command threatdna-report generates a severity/risk report from an
+// already-built genome store, grouped by actor and platform, and renders it
+// as HTML, Markdown, or JSON.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"threatdna/internal/threatdnacore"
+)
+
+const genomeDBPath = "threats.bleve/test_genomes.db"
+
+func main() {
+	formatFlag := flag.String("format", "html", "report format: html, markdown, or json")
+	outFlag := flag.String("out", "", "output file path (defaults to stdout)")
+	actorFlag := flag.String("actor", "", "filter: actor name substring")
+	campaignFlag := flag.String("campaign", "", "filter: campaign name substring")
+	platformFlag := flag.String("platform", "", "filter: platform substring")
+	tacticFlag := flag.String("tactic", "", "filter: tactic")
+	cveFlag := flag.String("cve", "", "filter: CVE ID")
+	ttpsFlag := flag.String("ttps", "", "filter: comma-separated technique IDs the genome must contain")
+	minConfidenceFlag := flag.Float64("min-confidence", 0, "filter: minimum genome confidence")
+	dateFromFlag := flag.String("date-from", "", "filter: only genomes last seen on or after this date (YYYY-MM-DD)")
+	dateToFlag := flag.String("date-to", "", "filter: only genomes first seen on or before this date (YYYY-MM-DD)")
+	flag.Parse()
+
+	filter := threatdnacore.ExportFilter{
+		Actor:         *actorFlag,
+		Campaign:      *campaignFlag,
+		Platform:      *platformFlag,
+		Tactic:        *tacticFlag,
+		CVE:           *cveFlag,
+		MinConfidence: *minConfidenceFlag,
+	}
+	if *ttpsFlag != "" {
+		filter.TTPs = strings.Split(*ttpsFlag, ",")
+	}
+	if *dateFromFlag != "" {
+		t, err := time.Parse("2006-01-02", *dateFromFlag)
+		if err != nil {
+			log.Fatalf("Invalid -date-from %q: %v", *dateFromFlag, err)
+		}
+		filter.DateFrom = t
+	}
+	if *dateToFlag != "" {
+		t, err := time.Parse("2006-01-02", *dateToFlag)
+		if err != nil {
+			log.Fatalf("Invalid -date-to %q: %v", *dateToFlag, err)
+		}
+		filter.DateTo = t
+	}
+
+	builder, err := threatdnacore.NewGenomeBuilder(genomeDBPath, "", "")
+	if err != nil {
+		log.Fatalf("Failed to open genome store: %v", err)
+	}
+	defer builder.Close()
+
+	reporter := threatdnacore.NewRiskReporter(builder)
+	report, err := reporter.GenerateReport(filter)
+	if err != nil {
+		log.Fatalf("Failed to generate risk report: %v", err)
+	}
+
+	var data []byte
+	switch *formatFlag {
+	case "html":
+		data, err = report.RenderHTML()
+	case "markdown", "md":
+		data, err = report.RenderMarkdown()
+	case "json":
+		data, err = report.RenderJSON()
+	default:
+		log.Fatalf("Unknown -format %q: must be html, markdown, or json", *formatFlag)
+	}
+	if err != nil {
+		log.Fatalf("Failed to render risk report: %v", err)
+	}
+
+	if *outFlag == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*outFlag, data, 0644); err != nil {
+		log.Fatalf("Failed to write report to %s: %v", *outFlag, err)
+	}
+	log.Printf("✅ Wrote %s risk report to %s", *formatFlag, *outFlag)
+}
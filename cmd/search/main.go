@@ -2,18 +2,140 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
 	"github.com/rs/cors"
 
 	"threatdna/internal/threatdnacore"
 )
 
 const indexPath = "threats.bleve"
+const genomeDBPath = "threats.bleve/test_genomes.db"
+const exportDir = "threats.bleve/exports"
 const listenPort = ":8080"
 
+// exporter is shared across requests so export jobs stay visible to later
+// GET /exports/:id polls on the same server process.
+var exporter *threatdnacore.Exporter
+
+// cache is the optional Redis hot-path cache; nil when --redis-url was not
+// given, in which case searchHandler always hits Bleve directly.
+var cache *threatdnacore.RedisCache
+
+// genomeSearchRequest is the body accepted by /api/genome-search.
+type genomeSearchRequest struct {
+	TTPs   []string `json:"ttps"`
+	MinLCS int      `json:"min_lcs"`
+}
+
+// genomeSearchHandler answers "which past campaigns share this attack
+// chain?" by decomposing the requested TTP sequence into trigrams and
+// ranking genomes by longest-common-subsequence length and Jaccard
+// similarity of their technique sets.
+func genomeSearchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req genomeSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.TTPs) == 0 {
+		http.Error(w, "Field 'ttps' is required", http.StatusBadRequest)
+		return
+	}
+	if req.MinLCS <= 0 {
+		req.MinLCS = 3
+	}
+
+	builder, err := threatdnacore.NewGenomeBuilder(genomeDBPath, "", "")
+	if err != nil {
+		log.Printf("Failed to open genome store: %v", err)
+		http.Error(w, "Internal server error: could not open genome store", http.StatusInternalServerError)
+		return
+	}
+	defer builder.Close()
+
+	genomes, err := builder.ListGenomes("", "", 0)
+	if err != nil {
+		log.Printf("Failed to list genomes: %v", err)
+		http.Error(w, "Internal server error: could not list genomes", http.StatusInternalServerError)
+		return
+	}
+
+	genomeIndex := threatdnacore.NewGenomeIndexFromGenomes(genomes)
+	results := genomeIndex.Search(req.TTPs, req.MinLCS)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		log.Printf("Failed to encode genome search results: %v", err)
+		http.Error(w, "Internal server error: could not encode results", http.StatusInternalServerError)
+	}
+}
+
+// sequenceSearchHandler answers "which genomes exhibit this behavioral
+// sequence?" against the ttp_sequence shingle field, e.g.
+// /api/sequence-search?seq=APT29,T1078,T1053,T1547&slop=1
+func sequenceSearchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	seqStr := r.URL.Query().Get("seq")
+	if seqStr == "" {
+		http.Error(w, "Query parameter 'seq' is required", http.StatusBadRequest)
+		return
+	}
+
+	slop := 0
+	if slopStr := r.URL.Query().Get("slop"); slopStr != "" {
+		parsedSlop, err := strconv.Atoi(slopStr)
+		if err != nil {
+			http.Error(w, "Query parameter 'slop' must be an integer", http.StatusBadRequest)
+			return
+		}
+		slop = parsedSlop
+	}
+
+	hits, err := threatdnacore.RunSequenceSearch(indexPath, strings.Split(seqStr, ","), slop)
+	if err != nil {
+		log.Printf("Sequence search failed: %v", err)
+		http.Error(w, "Internal server error: sequence search failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(hits); err != nil {
+		log.Printf("Failed to encode sequence search results: %v", err)
+		http.Error(w, "Internal server error: could not encode results", http.StatusInternalServerError)
+	}
+}
+
 // searchHandler handles search requests from the frontend
 func searchHandler(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers for all responses
@@ -33,6 +155,18 @@ func searchHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cacheFilters := map[string]string{
+		"min_cvss":      r.URL.Query().Get("min_cvss"),
+		"kev_exploited": r.URL.Query().Get("kev_exploited"),
+	}
+	if cache != nil {
+		if cached, ok := cache.Search.Get(queryStr, cacheFilters); ok {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(cached)
+			return
+		}
+	}
+
 	index, err := bleve.Open(indexPath)
 	if err != nil {
 		log.Printf("Failed to open index: %v", err)
@@ -41,9 +175,28 @@ func searchHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer index.Close()
 
-	query := bleve.NewMatchQuery(queryStr)
-	searchRequest := bleve.NewSearchRequest(query)
-	searchRequest.Fields = []string{"id", "actor", "campaign"}
+	conjuncts := []query.Query{bleve.NewMatchQuery(queryStr)}
+
+	if minCVSSStr := r.URL.Query().Get("min_cvss"); minCVSSStr != "" {
+		minCVSS, err := strconv.ParseFloat(minCVSSStr, 64)
+		if err != nil {
+			http.Error(w, "Query parameter 'min_cvss' must be a number", http.StatusBadRequest)
+			return
+		}
+		cvssQuery := bleve.NewNumericRangeQuery(&minCVSS, nil)
+		cvssQuery.SetField("cvss_score")
+		conjuncts = append(conjuncts, cvssQuery)
+	}
+
+	if r.URL.Query().Get("kev_exploited") == "true" {
+		kevQuery := bleve.NewBoolFieldQuery(true)
+		kevQuery.SetField("kev_exploited")
+		conjuncts = append(conjuncts, kevQuery)
+	}
+
+	var combined query.Query = bleve.NewConjunctionQuery(conjuncts...)
+	searchRequest := bleve.NewSearchRequest(combined)
+	searchRequest.Fields = []string{"id", "actor", "campaign", "cvss_score", "kev_exploited"}
 	searchRequest.Size = 10 // Limit results for API
 
 	searchResults, err := index.Search(searchRequest)
@@ -73,14 +226,36 @@ func searchHandler(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		maxCVSS := 0.0
+		if c, ok := hit.Fields["cvss_score"]; ok {
+			if cvssFloat, isFloat := c.(float64); isFloat {
+				maxCVSS = cvssFloat
+			}
+		}
+
+		kev := false
+		if k, ok := hit.Fields["kev_exploited"]; ok {
+			if kevBool, isBool := k.(bool); isBool {
+				kev = kevBool
+			}
+		}
+
 		apiResults = append(apiResults, threatdnacore.APISearchResult{
 			ID:       hit.ID,
 			Actor:    actor,
 			Campaign: campaign,
 			Score:    hit.Score,
+			MaxCVSS:  maxCVSS,
+			KEV:      kev,
 		})
 	}
 
+	if cache != nil {
+		if err := cache.Search.Put(queryStr, cacheFilters, apiResults); err != nil {
+			log.Printf("⚠️  Failed to cache search results: %v", err)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(apiResults); err != nil {
 		log.Printf("Failed to encode search results: %v", err)
@@ -88,19 +263,148 @@ func searchHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// exportRequest is the body accepted by POST /api/export.
+type exportRequest struct {
+	Actor         string   `json:"actor"`
+	Campaign      string   `json:"campaign"`
+	TTPs          []string `json:"ttps"`
+	Tactic        string   `json:"tactic"`
+	Platform      string   `json:"platform"`
+	CVE           string   `json:"cve"`
+	DateFrom      string   `json:"date_from"`
+	DateTo        string   `json:"date_to"`
+	MinConfidence float64  `json:"min_confidence"`
+	Format        string   `json:"format"`
+}
+
+// submitExportHandler starts an async genome export job and returns its ID;
+// poll GET /exports/:id for status and GET /exports/:id/download once
+// completed.
+func submitExportHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req exportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := threatdnacore.ExportFormat(req.Format)
+	switch format {
+	case threatdnacore.ExportFormatCSV, threatdnacore.ExportFormatJSONL, threatdnacore.ExportFormatSTIX2:
+	default:
+		http.Error(w, "Field 'format' must be one of csv, jsonl, stix2", http.StatusBadRequest)
+		return
+	}
+
+	filter := threatdnacore.ExportFilter{
+		Actor:         req.Actor,
+		Campaign:      req.Campaign,
+		TTPs:          req.TTPs,
+		Tactic:        req.Tactic,
+		Platform:      req.Platform,
+		CVE:           req.CVE,
+		MinConfidence: req.MinConfidence,
+	}
+	if req.DateFrom != "" {
+		if t, err := time.Parse("2006-01-02", req.DateFrom); err == nil {
+			filter.DateFrom = t
+		}
+	}
+	if req.DateTo != "" {
+		if t, err := time.Parse("2006-01-02", req.DateTo); err == nil {
+			filter.DateTo = t
+		}
+	}
+
+	jobID := exporter.SubmitExport(filter, format)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"execution_id": jobID})
+}
+
+// exportsHandler serves GET /exports/:id (job status) and
+// GET /exports/:id/download (the artifact, once the job has completed).
+func exportsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/exports/")
+	download := strings.HasSuffix(path, "/download")
+	jobID := strings.TrimSuffix(path, "/download")
+
+	job, ok := exporter.GetJob(jobID)
+	if !ok {
+		http.Error(w, "Export job not found", http.StatusNotFound)
+		return
+	}
+
+	if !download {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job)
+		return
+	}
+
+	if job.Status != threatdnacore.ExportStatusCompleted {
+		http.Error(w, fmt.Sprintf("Export job %s is not ready for download (status: %s)", jobID, job.Status), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(job.ArtifactPath)))
+	http.ServeFile(w, r, job.ArtifactPath)
+}
+
 func main() {
+	redisURLFlag := flag.String("redis-url", "", "optional Redis URL (e.g. redis://localhost:6379/0) for the hot-path search cache")
+	flag.Parse()
+
 	log.Printf("Starting ThreatDNA Search API on port %s", listenPort)
 
+	if *redisURLFlag != "" {
+		redisCache, err := threatdnacore.NewRedisCache(*redisURLFlag, 0)
+		if err != nil {
+			log.Fatalf("Failed to connect to redis at %s: %v", *redisURLFlag, err)
+		}
+		defer redisCache.Close()
+		cache = redisCache
+		log.Printf("Redis hot-path cache enabled at %s", *redisURLFlag)
+	}
+
+	builder, err := threatdnacore.NewGenomeBuilder(genomeDBPath, "", "")
+	if err != nil {
+		log.Fatalf("Failed to open genome store: %v", err)
+	}
+	defer builder.Close()
+	exporter = threatdnacore.NewExporter(builder, exportDir)
+
 	// Setup CORS
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"}, // Allow all origins for development
 		AllowCredentials: true,
 		AllowedMethods:   []string{"GET", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type"},
-	}) 
+	})
 
 	hm := http.NewServeMux()
 	hm.HandleFunc("/api/search", searchHandler)
+	hm.HandleFunc("/api/genome-search", genomeSearchHandler)
+	hm.HandleFunc("/api/sequence-search", sequenceSearchHandler)
+	hm.HandleFunc("/api/export", submitExportHandler)
+	hm.HandleFunc("/exports/", exportsHandler)
 
 	handler := c.Handler(hm)
 
@@ -0,0 +1,44 @@
+// Command threatdna-taxii polls a TAXII 2.1 collection on a schedule and
+// feeds the STIX objects it returns straight into a GenomeBuilder, as an
+// authoritative alternative to free-text HTML scraping.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"threatdna/internal/threatdnacore"
+	"threatdna/internal/threatdnacore/taxii"
+)
+
+const dbPath = "threats.bleve/test_genomes.db"
+
+func main() {
+	rootURLFlag := flag.String("root-url", "", "TAXII 2.1 API root URL, e.g. https://taxii.example.com/api1")
+	collectionIDFlag := flag.String("collection-id", "", "TAXII collection ID to poll")
+	intervalFlag := flag.Duration("interval", 5*time.Minute, "poll interval")
+	flag.Parse()
+
+	if *rootURLFlag == "" || *collectionIDFlag == "" {
+		log.Fatal("both -root-url and -collection-id are required")
+	}
+
+	builder, err := threatdnacore.NewGenomeBuilder(dbPath, "", "")
+	if err != nil {
+		log.Fatalf("Failed to create genome builder: %v", err)
+	}
+	defer builder.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	client := taxii.NewClient(nil)
+	log.Printf("Polling TAXII collection %s at %s every %v", *collectionIDFlag, *rootURLFlag, *intervalFlag)
+	if err := client.PollSchedule(ctx, *rootURLFlag, *collectionIDFlag, *intervalFlag, builder); err != nil && err != context.Canceled {
+		log.Fatalf("TAXII poll loop exited: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Command threatdna-templates re-evaluates an already-ingested CTI corpus
+// against a directory of DetectionTemplates without re-running ingestion.
+//
+// Usage:
+//
+//	threatdna-templates run <dir>
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"threatdna/internal/threatdnacore"
+)
+
+const ctiResultsPath = "cti_results.json"
+
+func main() {
+	if len(os.Args) < 3 || os.Args[1] != "run" {
+		fmt.Println("Usage: threatdna-templates run <dir>")
+		os.Exit(1)
+	}
+	templatesDir := os.Args[2]
+
+	engine, err := threatdnacore.LoadTemplateEngine(templatesDir)
+	if err != nil {
+		log.Fatalf("Failed to load templates from %s: %v", templatesDir, err)
+	}
+
+	data, err := ioutil.ReadFile(ctiResultsPath)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", ctiResultsPath, err)
+	}
+
+	var records []threatdnacore.CTIRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		log.Fatalf("Failed to parse %s: %v", ctiResultsPath, err)
+	}
+
+	hitCount := 0
+	for i := range records {
+		records[i].Detections = engine.Evaluate(&records[i])
+		if len(records[i].Detections) > 0 {
+			hitCount++
+		}
+	}
+
+	out, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal updated records: %v", err)
+	}
+	if err := ioutil.WriteFile(ctiResultsPath, out, 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", ctiResultsPath, err)
+	}
+
+	log.Printf("🎯 Re-evaluated %d records against templates in %s, %d matched at least one detection", len(records), templatesDir, hitCount)
+}
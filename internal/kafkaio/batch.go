@@ -0,0 +1,112 @@
+package kafkaio
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchConfig tunes BatchPublisher's pipelining. kafka-go's Writer already
+// batches internally (BatchSize/BatchBytes/BatchTimeout on the Writer
+// itself govern the wire-level batching); Workers controls how many
+// Produce calls — each already able to trigger one of those writer-level
+// batches — are in flight concurrently.
+type BatchConfig struct {
+	Workers      int
+	BatchSize    int
+	BatchBytes   int64
+	BatchTimeout time.Duration
+}
+
+// DefaultBatchConfig matches kafka-go's own Writer defaults, with a
+// handful of workers so a single slow broker round trip doesn't stall
+// every other pending record behind it.
+func DefaultBatchConfig() BatchConfig {
+	return BatchConfig{
+		Workers:      4,
+		BatchSize:    100,
+		BatchBytes:   1048576,
+		BatchTimeout: 1 * time.Second,
+	}
+}
+
+// DeliveryReport is BatchPublisher's async result for one published
+// Record: either its outcome was success (Err is nil) or it failed after
+// the underlying Producer's own interceptor/retry handling.
+type DeliveryReport struct {
+	Record *Record
+	Err    error
+}
+
+// PublishFunc is anything that can take a Record and report its outcome —
+// typically a Producer.Produce or a threatdnacore.DLQPublisher.
+// PublishWithDLQ method value, so BatchPublisher pipelines the same
+// failure semantics the caller already configured instead of bypassing
+// them.
+type PublishFunc func(ctx context.Context, rec *Record) error
+
+// BatchPublisher fans Records pushed via Publish into cfg.Workers
+// goroutines, each calling the configured PublishFunc, and reports each
+// outcome asynchronously over Reports(). The input channel is bounded
+// (cfg.Workers*cfg.BatchSize deep), so Publish blocks once that many
+// records are queued — backpressure instead of unbounded buffering when
+// the broker falls behind.
+type BatchPublisher struct {
+	publish PublishFunc
+
+	in      chan *Record
+	reports chan DeliveryReport
+	wg      sync.WaitGroup
+}
+
+// NewBatchPublisher starts cfg.Workers goroutines draining into publish.
+// Call Close once every record has been handed to Publish, to stop the
+// workers and close Reports().
+func NewBatchPublisher(ctx context.Context, publish PublishFunc, cfg BatchConfig) *BatchPublisher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1
+	}
+
+	bp := &BatchPublisher{
+		publish: publish,
+		in:      make(chan *Record, cfg.Workers*cfg.BatchSize),
+		reports: make(chan DeliveryReport, cfg.Workers*cfg.BatchSize),
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		bp.wg.Add(1)
+		go bp.worker(ctx)
+	}
+	return bp
+}
+
+func (bp *BatchPublisher) worker(ctx context.Context) {
+	defer bp.wg.Done()
+	for rec := range bp.in {
+		err := bp.publish(ctx, rec)
+		bp.reports <- DeliveryReport{Record: rec, Err: err}
+	}
+}
+
+// Publish enqueues rec for an available worker, blocking if the input
+// channel is already full.
+func (bp *BatchPublisher) Publish(rec *Record) {
+	bp.in <- rec
+}
+
+// Reports returns the channel DeliveryReports are sent on, one per
+// Publish call, in completion order rather than submission order.
+func (bp *BatchPublisher) Reports() <-chan DeliveryReport {
+	return bp.reports
+}
+
+// Close stops accepting new records, waits for every in-flight Publish
+// call's worker to finish, and closes Reports().
+func (bp *BatchPublisher) Close() {
+	close(bp.in)
+	bp.wg.Wait()
+	close(bp.reports)
+}
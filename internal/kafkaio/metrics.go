@@ -0,0 +1,167 @@
+package kafkaio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBucketBoundsMS are the Prometheus-style histogram bucket upper
+// bounds (in milliseconds) MetricsInterceptor tracks publish latency
+// against. +Inf is implicit, matching the text-exposition format.
+var latencyBucketBoundsMS = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000}
+
+// MetricsInterceptor records records/sec, bytes/sec, and publish latency
+// per topic and exposes them in Prometheus text-exposition format, the
+// same hand-rolled approach cmd/dashboard's /metrics endpoint uses rather
+// than pulling in the official client library.
+type MetricsInterceptor struct {
+	mu         sync.Mutex
+	perTopic   map[string]*topicMetrics
+	inFlightAt map[string]time.Time // keyed by record key, set in OnProduce
+}
+
+type topicMetrics struct {
+	records uint64
+	bytes   uint64
+	errors  uint64
+	buckets []uint64 // parallel to latencyBucketBoundsMS, plus one +Inf overflow bucket
+	sum     float64  // total latency in ms, for the histogram's _sum line
+}
+
+// NewMetricsInterceptor returns an empty MetricsInterceptor.
+func NewMetricsInterceptor() *MetricsInterceptor {
+	return &MetricsInterceptor{
+		perTopic:   make(map[string]*topicMetrics),
+		inFlightAt: make(map[string]time.Time),
+	}
+}
+
+func (m *MetricsInterceptor) OnProduce(ctx context.Context, rec *Record) (*Record, error) {
+	m.mu.Lock()
+	m.inFlightAt[string(rec.Key)] = time.Now()
+	m.mu.Unlock()
+	return rec, nil
+}
+
+func (m *MetricsInterceptor) OnAck(ctx context.Context, rec *Record, partition int, offset int64) {
+	m.record(rec, nil)
+}
+
+func (m *MetricsInterceptor) OnError(ctx context.Context, rec *Record, err error) {
+	m.record(rec, err)
+}
+
+func (m *MetricsInterceptor) record(rec *Record, produceErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	start, ok := m.inFlightAt[string(rec.Key)]
+	if ok {
+		delete(m.inFlightAt, string(rec.Key))
+	}
+
+	tm, ok := m.perTopic[rec.Topic]
+	if !ok {
+		tm = &topicMetrics{buckets: make([]uint64, len(latencyBucketBoundsMS)+1)}
+		m.perTopic[rec.Topic] = tm
+	}
+
+	if produceErr != nil {
+		atomic.AddUint64(&tm.errors, 1)
+		return
+	}
+
+	atomic.AddUint64(&tm.records, 1)
+	atomic.AddUint64(&tm.bytes, uint64(len(rec.Value)))
+
+	if !start.IsZero() {
+		latencyMS := float64(time.Since(start).Microseconds()) / 1000.0
+		tm.sum += latencyMS
+		placed := false
+		for i, bound := range latencyBucketBoundsMS {
+			if latencyMS <= bound {
+				tm.buckets[i]++
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			tm.buckets[len(latencyBucketBoundsMS)]++
+		}
+	}
+}
+
+// WriteTo renders the accumulated counters in Prometheus text-exposition
+// format, so a caller's /metrics handler can copy it straight to the
+// response body.
+func (m *MetricsInterceptor) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	topics := make([]string, 0, len(m.perTopic))
+	snapshot := make(map[string]topicMetrics, len(m.perTopic))
+	for topic, tm := range m.perTopic {
+		topics = append(topics, topic)
+		snapshot[topic] = *tm
+	}
+	m.mu.Unlock()
+	sort.Strings(topics)
+
+	buf := &countingWriter{w: w}
+
+	fmt.Fprintln(buf, "# HELP threatdna_producer_records_total Records successfully published per topic.")
+	fmt.Fprintln(buf, "# TYPE threatdna_producer_records_total counter")
+	for _, topic := range topics {
+		fmt.Fprintf(buf, "threatdna_producer_records_total{topic=%q} %d\n", topic, snapshot[topic].records)
+	}
+
+	fmt.Fprintln(buf, "# HELP threatdna_producer_bytes_total Bytes successfully published per topic.")
+	fmt.Fprintln(buf, "# TYPE threatdna_producer_bytes_total counter")
+	for _, topic := range topics {
+		fmt.Fprintf(buf, "threatdna_producer_bytes_total{topic=%q} %d\n", topic, snapshot[topic].bytes)
+	}
+
+	fmt.Fprintln(buf, "# HELP threatdna_producer_errors_total Failed publish attempts per topic.")
+	fmt.Fprintln(buf, "# TYPE threatdna_producer_errors_total counter")
+	for _, topic := range topics {
+		fmt.Fprintf(buf, "threatdna_producer_errors_total{topic=%q} %d\n", topic, snapshot[topic].errors)
+	}
+
+	fmt.Fprintln(buf, "# HELP threatdna_producer_publish_latency_ms Publish latency in milliseconds.")
+	fmt.Fprintln(buf, "# TYPE threatdna_producer_publish_latency_ms histogram")
+	for _, topic := range topics {
+		tm := snapshot[topic]
+		var cumulative uint64
+		for i, bound := range latencyBucketBoundsMS {
+			cumulative += tm.buckets[i]
+			fmt.Fprintf(buf, "threatdna_producer_publish_latency_ms_bucket{topic=%q,le=%q} %d\n", topic, fmt.Sprintf("%g", bound), cumulative)
+		}
+		cumulative += tm.buckets[len(latencyBucketBoundsMS)]
+		fmt.Fprintf(buf, "threatdna_producer_publish_latency_ms_bucket{topic=%q,le=\"+Inf\"} %d\n", topic, cumulative)
+		fmt.Fprintf(buf, "threatdna_producer_publish_latency_ms_sum{topic=%q} %g\n", topic, tm.sum)
+		fmt.Fprintf(buf, "threatdna_producer_publish_latency_ms_count{topic=%q} %d\n", topic, cumulative)
+	}
+
+	return buf.n, buf.err
+}
+
+// countingWriter tracks bytes written so WriteTo can satisfy io.WriterTo's
+// (int64, error) signature atop repeated fmt.Fprint* calls.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,101 @@
+package kafkaio
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// RedactionInterceptor drops or masks fields on records marked with a
+// restrictive TLP (Traffic Light Protocol) or PAP (Permissible Actions
+// Protocol) tag before they leave the building over Kafka. Markings are
+// read from a "tags" array in the record's JSON value, the same field
+// threatdnacore.CTIRecord.Tags serializes to.
+type RedactionInterceptor struct {
+	// RedactTLP lists TLP levels (case-insensitive, e.g. "red", "amber")
+	// whose records have RawText/raw_text blanked before publish.
+	RedactTLP []string
+	// BlockPAP lists PAP levels whose records are dropped entirely
+	// (OnProduce returns an error) rather than redacted, since PAP governs
+	// whether the data may be acted on/shared at all.
+	BlockPAP []string
+}
+
+// NewRedactionInterceptor returns a RedactionInterceptor with the common
+// defaults: TLP:RED records have their raw text redacted, PAP:RED records
+// are blocked outright.
+func NewRedactionInterceptor() *RedactionInterceptor {
+	return &RedactionInterceptor{
+		RedactTLP: []string{"red"},
+		BlockPAP:  []string{"red"},
+	}
+}
+
+func (r *RedactionInterceptor) OnProduce(ctx context.Context, rec *Record) (*Record, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(rec.Value, &payload); err != nil {
+		// Not a JSON record (e.g. already re-encoded by a downstream codec);
+		// nothing for tag-based redaction to act on.
+		return rec, nil
+	}
+
+	tags, _ := payload["tags"].([]interface{})
+	for _, t := range tags {
+		tag, ok := t.(string)
+		if !ok {
+			continue
+		}
+		marking, level := splitMarking(tag)
+		switch marking {
+		case "pap":
+			if containsFold(r.BlockPAP, level) {
+				return nil, errBlockedByPAP(level)
+			}
+		case "tlp":
+			if containsFold(r.RedactTLP, level) {
+				payload["raw_text"] = "[REDACTED]"
+			}
+		}
+	}
+
+	redacted, err := json.Marshal(payload)
+	if err != nil {
+		return rec, nil
+	}
+	out := *rec
+	out.Value = redacted
+	return &out, nil
+}
+
+func (r *RedactionInterceptor) OnAck(ctx context.Context, rec *Record, partition int, offset int64) {}
+func (r *RedactionInterceptor) OnError(ctx context.Context, rec *Record, err error)                 {}
+
+// splitMarking parses a "tlp:red" / "pap:amber" style tag into its
+// lowercased protocol name and level. A tag without a ':' separator
+// returns ("", "").
+func splitMarking(tag string) (marking, level string) {
+	parts := strings.SplitN(tag, ":", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return strings.ToLower(parts[0]), strings.ToLower(parts[1])
+}
+
+func containsFold(list []string, want string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
+
+type papBlockError string
+
+func (e papBlockError) Error() string {
+	return "record marked PAP:" + string(e) + " may not be published"
+}
+
+func errBlockedByPAP(level string) error {
+	return papBlockError(level)
+}
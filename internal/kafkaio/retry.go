@@ -0,0 +1,55 @@
+package kafkaio
+
+import (
+	"crypto/rand"
+	"math"
+	mathrand "math/rand"
+	"time"
+)
+
+// RetryPolicy bounds how many times, and how long between attempts,
+// PublishWithRetry retries a failed publish before giving up on it.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts, including the first; 0 disables retries
+	BaseDelay   time.Duration // delay before the second attempt
+	MaxDelay    time.Duration // backoff ceiling, before jitter is applied
+}
+
+// DefaultRetryPolicy retries a failed publish three additional times with
+// exponential backoff starting at 100ms, capped at 5s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}
+}
+
+// Delay returns the backoff before attempt (1-indexed: the delay before
+// the 2nd, 3rd, ... attempt), as exponential backoff with up to 50%
+// jitter so many retrying producers don't all retry in lockstep.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	backoff := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if max := float64(p.MaxDelay); p.MaxDelay > 0 && backoff > max {
+		backoff = max
+	}
+	jitter := backoff * 0.5 * mathrand.Float64()
+	return time.Duration(backoff + jitter)
+}
+
+// TraceID returns a short random hex identifier PublishWithDLQ stamps onto
+// dead-lettered records, so a failure can be correlated across the
+// original publish attempts and the DLQ entry without depending on
+// Kafka-assigned offsets.
+func TraceID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand failing is effectively unrecoverable on any real
+		// platform; fall back to a fixed marker rather than panicking over
+		// what is only a correlation aid.
+		return "unknown"
+	}
+	const hex = "0123456789abcdef"
+	out := make([]byte, 16)
+	for i, c := range b {
+		out[i*2] = hex[c>>4]
+		out[i*2+1] = hex[c&0xf]
+	}
+	return string(out)
+}
@@ -0,0 +1,85 @@
+package kafkaio
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies this package's spans in whatever OpenTelemetry
+// exporter the host process has configured.
+const tracerName = "threatdna/internal/kafkaio"
+
+// TracingInterceptor opens an OpenTelemetry span around each record's
+// round trip through the Producer, so a publish can be correlated with
+// the ingest/build spans that produced it in a distributed trace. It
+// keeps in-flight spans keyed by record key, the same pattern
+// MetricsInterceptor uses to correlate OnProduce with OnAck/OnError.
+type TracingInterceptor struct {
+	tracer trace.Tracer
+
+	mu    sync.Mutex
+	spans map[string]trace.Span
+}
+
+// NewTracingInterceptor returns a TracingInterceptor using the global
+// OpenTelemetry TracerProvider. Call otel.SetTracerProvider before
+// constructing it if the host process exports spans anywhere other than
+// the default no-op provider.
+func NewTracingInterceptor() *TracingInterceptor {
+	return &TracingInterceptor{
+		tracer: otel.Tracer(tracerName),
+		spans:  make(map[string]trace.Span),
+	}
+}
+
+func (t *TracingInterceptor) OnProduce(ctx context.Context, rec *Record) (*Record, error) {
+	_, span := t.tracer.Start(ctx, "kafkaio.Produce", trace.WithAttributes(
+		attribute.String("messaging.destination", rec.Topic),
+		attribute.String("messaging.kafka.message_key", string(rec.Key)),
+		attribute.Int("messaging.message_payload_size_bytes", len(rec.Value)),
+	))
+
+	t.mu.Lock()
+	t.spans[string(rec.Key)] = span
+	t.mu.Unlock()
+
+	return rec, nil
+}
+
+func (t *TracingInterceptor) OnAck(ctx context.Context, rec *Record, partition int, offset int64) {
+	span := t.takeSpan(rec)
+	if span == nil {
+		return
+	}
+	if partition >= 0 {
+		span.SetAttributes(attribute.Int("messaging.kafka.partition", partition))
+	}
+	span.SetStatus(codes.Ok, "")
+	span.End()
+}
+
+func (t *TracingInterceptor) OnError(ctx context.Context, rec *Record, err error) {
+	span := t.takeSpan(rec)
+	if span == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.End()
+}
+
+func (t *TracingInterceptor) takeSpan(rec *Record) trace.Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span, ok := t.spans[string(rec.Key)]
+	if !ok {
+		return nil
+	}
+	delete(t.spans, string(rec.Key))
+	return span
+}
@@ -0,0 +1,98 @@
+package threatdnacore
+
+import (
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+// KafkaAdmin wraps Sarama's ClusterAdmin for the topic/partition operations
+// a ThreatDNA deployment needs: creating the CTI topic, inspecting its
+// partitions, and reassigning replicas across brokers (KIP-455) so builder
+// load can be redistributed without a cluster restart.
+type KafkaAdmin struct {
+	admin sarama.ClusterAdmin
+}
+
+// NewKafkaAdmin connects a ClusterAdmin to brokers.
+func NewKafkaAdmin(brokers []string) (*KafkaAdmin, error) {
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_8_0_0
+
+	admin, err := sarama.NewClusterAdmin(brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka cluster admin: %w", err)
+	}
+	return &KafkaAdmin{admin: admin}, nil
+}
+
+// Close releases the underlying admin connection.
+func (ka *KafkaAdmin) Close() error {
+	return ka.admin.Close()
+}
+
+// CreateTopics creates topic if it does not already exist, tolerating
+// ErrTopicAlreadyExists so setup code can call it idempotently.
+func (ka *KafkaAdmin) CreateTopics(topic string, numPartitions int32, replicationFactor int16) error {
+	err := ka.admin.CreateTopic(topic, &sarama.TopicDetail{
+		NumPartitions:     numPartitions,
+		ReplicationFactor: replicationFactor,
+	}, false)
+	if err != nil {
+		if topicErr, ok := err.(*sarama.TopicError); ok && topicErr.Err == sarama.ErrTopicAlreadyExists {
+			return nil
+		}
+		return fmt.Errorf("failed to create topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// DescribeTopics returns partition/replica/ISR metadata for the given topics.
+func (ka *KafkaAdmin) DescribeTopics(topics ...string) ([]*sarama.TopicMetadata, error) {
+	metadata, err := ka.admin.DescribeTopics(topics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe topics %v: %w", topics, err)
+	}
+	return metadata, nil
+}
+
+// DeleteTopics deletes one or more topics.
+func (ka *KafkaAdmin) DeleteTopics(topics ...string) error {
+	for _, topic := range topics {
+		if err := ka.admin.DeleteTopic(topic); err != nil {
+			return fmt.Errorf("failed to delete topic %s: %w", topic, err)
+		}
+	}
+	return nil
+}
+
+// AlterPartitionReassignments submits a KIP-455 reassignment plan for topic:
+// partition -> ordered list of broker IDs that should host it.
+func (ka *KafkaAdmin) AlterPartitionReassignments(topic string, plan map[int32][]int32) error {
+	maxPartition := int32(-1)
+	for partition := range plan {
+		if partition > maxPartition {
+			maxPartition = partition
+		}
+	}
+
+	assignment := make([][]int32, maxPartition+1)
+	for partition, replicas := range plan {
+		assignment[partition] = replicas
+	}
+
+	if err := ka.admin.AlterPartitionReassignments(topic, assignment); err != nil {
+		return fmt.Errorf("failed to alter partition reassignments for topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// ListPartitionReassignments reports the status of any in-progress
+// reassignments for topic. A nil partitions slice reports on all partitions.
+func (ka *KafkaAdmin) ListPartitionReassignments(topic string, partitions []int32) (map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus, error) {
+	status, err := ka.admin.ListPartitionReassignments(topic, partitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partition reassignments for topic %s: %w", topic, err)
+	}
+	return status, nil
+}
@@ -0,0 +1,147 @@
+package threatdnacore
+
+// acPatternKind distinguishes which part of a technique contributed a
+// matched pattern (its name, its MITRE ID, or a long keyword), since they
+// carry different word-boundary semantics: the original regex-based
+// extractor wrapped ID/keyword patterns in \b...\b but matched the
+// technique name as a bare substring.
+type acPatternKind int
+
+const (
+	acPatternName acPatternKind = iota
+	acPatternID
+	acPatternKeyword
+)
+
+// acPayload is attached to the trie node a pattern terminates on, so a
+// single automaton pass can attribute every match back to the technique
+// (and pattern kind/length) that produced it.
+type acPayload struct {
+	techniqueID string
+	kind        acPatternKind
+	length      int
+}
+
+type acNode struct {
+	children map[byte]*acNode
+	fail     *acNode
+	output   []acPayload
+}
+
+func newACNode() *acNode {
+	return &acNode{children: make(map[byte]*acNode)}
+}
+
+// ahoCorasick is a multi-pattern string matcher built once in
+// NewTechniqueExtractor and reused across every ExtractTTPs call,
+// replacing what used to be ~600 independently compiled regexes run
+// against every document with a single linear pass over the text.
+type ahoCorasick struct {
+	root *acNode
+}
+
+func newAhoCorasick() *ahoCorasick {
+	return &ahoCorasick{root: newACNode()}
+}
+
+// insert adds pattern (expected already lowercased) to the trie, with
+// payload recorded on its terminal node. Call build once after every
+// insert, before the first search.
+func (a *ahoCorasick) insert(pattern string, payload acPayload) {
+	if pattern == "" {
+		return
+	}
+	node := a.root
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		child, ok := node.children[c]
+		if !ok {
+			child = newACNode()
+			node.children[c] = child
+		}
+		node = child
+	}
+	node.output = append(node.output, payload)
+}
+
+// build computes failure links via a breadth-first walk of the trie and
+// propagates each node's failure node's output set into its own, turning
+// the trie into a complete Aho-Corasick automaton.
+func (a *ahoCorasick) build() {
+	queue := make([]*acNode, 0, len(a.root.children))
+	for _, child := range a.root.children {
+		child.fail = a.root
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for c, child := range node.children {
+			queue = append(queue, child)
+
+			failNode := node.fail
+			for failNode != nil {
+				if next, ok := failNode.children[c]; ok {
+					child.fail = next
+					break
+				}
+				failNode = failNode.fail
+			}
+			if child.fail == nil {
+				child.fail = a.root
+			}
+			child.output = append(child.output, child.fail.output...)
+		}
+	}
+}
+
+// acMatch is one emitted pattern match: end is the exclusive byte offset
+// the match ends at in the scanned text, so its start is end-payload.length.
+type acMatch struct {
+	end     int
+	payload acPayload
+}
+
+// search runs a single linear pass over text (expected already lowercased
+// to match how patterns were inserted) and emits every pattern match,
+// including overlapping ones, via the precomputed failure links.
+func (a *ahoCorasick) search(text string) []acMatch {
+	var matches []acMatch
+	node := a.root
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		for node != a.root {
+			if _, ok := node.children[c]; ok {
+				break
+			}
+			node = node.fail
+		}
+		if next, ok := node.children[c]; ok {
+			node = next
+		}
+		for _, payload := range node.output {
+			matches = append(matches, acMatch{end: i + 1, payload: payload})
+		}
+	}
+	return matches
+}
+
+// isWordBoundary reports whether [start, end) in text is bounded by
+// non-word bytes on both sides (or the start/end of text), the same
+// semantics the original \b...\b regex patterns enforced for technique
+// IDs and keywords.
+func isWordBoundary(text string, start, end int) bool {
+	if start > 0 && isWordByte(text[start-1]) {
+		return false
+	}
+	if end < len(text) && isWordByte(text[end]) {
+		return false
+	}
+	return true
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
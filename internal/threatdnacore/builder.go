@@ -7,25 +7,72 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/IBM/sarama"
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/token/shingle"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/whitespace"
 	"github.com/blevesearch/bleve/v2/mapping"
-	"github.com/segmentio/kafka-go"
+	"github.com/blevesearch/bleve/v2/search"
+	bleveQuery "github.com/blevesearch/bleve/v2/search/query"
 )
 
+// builderConsumerGroup is the Sarama consumer group ID all builder replicas
+// join, so Kafka rebalances partitions across the fleet instead of every
+// replica reading every partition.
+const builderConsumerGroup = "threatdna-builder-group"
+
+// genomeStateTopic is the compacted Kafka topic every finalized genome is
+// published to, keyed by genome ID, so a View can materialize current
+// genome state without polling Bleve.
+const genomeStateTopic = "threatdna-genomes"
+
 // GenomeBuilder processes CTI records, builds threat genomes, and manages their storage and Kafka interactions.
 type GenomeBuilder struct {
-	db          bleve.Index
-	kafkaReader *kafka.Reader
-	dbPath      string
-	mu          sync.Mutex // Mutex to protect concurrent DB access
+	db             bleve.Index
+	consumerGroup  sarama.ConsumerGroup
+	genomeProducer sarama.SyncProducer
+	kafkaBroker    string
+	kafkaTopic     string
+	dbPath         string
+	cache          *RedisCache    // optional hot-path cache; nil when not configured
+	geoEnricher    *GeoIPEnricher // optional GeoIP lookups; never nil, but may have no databases loaded
+	mu             sync.Mutex // Mutex to protect concurrent DB access
+}
+
+// SetCache attaches an optional RedisCache to the builder. Once set, every
+// genome the builder indexes is also written through to Redis. Passing nil
+// disables the cache again.
+func (gb *GenomeBuilder) SetCache(cache *RedisCache) {
+	gb.cache = cache
+}
+
+// SetGeoEnricher attaches a GeoIPEnricher to the builder, overriding the one
+// NewGenomeBuilder loaded from GeoIPCityDBEnv/GeoIPASNDBEnv.
+func (gb *GenomeBuilder) SetGeoEnricher(enricher *GeoIPEnricher) {
+	gb.geoEnricher = enricher
 }
 
-// NewGenomeBuilder creates a new instance of GenomeBuilder.
+// EnrichIOCs runs the builder's GeoIPEnricher over iocs in place. It is a
+// no-op (not an error) if no GeoIP database was configured.
+func (gb *GenomeBuilder) EnrichIOCs(iocs []IOC) {
+	if gb.geoEnricher == nil {
+		return
+	}
+	gb.geoEnricher.EnrichIOCs(iocs)
+}
+
+// NewGenomeBuilder creates a new instance of GenomeBuilder. kafkaBroker/
+// kafkaTopic may be left empty when the builder is only used for Bleve
+// access (e.g. by the indexer/search CLIs) — no consumer group is created
+// in that case.
 func NewGenomeBuilder(dbPath, kafkaBroker, kafkaTopic string) (*GenomeBuilder, error) {
 	// Ensure the directory for the Bleve database exists
 	dir := dbPath[:len(dbPath)-len("/test_genomes.db")] // Extract directory from dbPath
@@ -47,65 +94,414 @@ func NewGenomeBuilder(dbPath, kafkaBroker, kafkaTopic string) (*GenomeBuilder, e
 		return nil, err
 	}
 
+	geoEnricher, err := NewGeoIPEnricher(os.Getenv(GeoIPCityDBEnv), os.Getenv(GeoIPASNDBEnv))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize GeoIP enricher: %w", err)
+	}
+
 	builder := &GenomeBuilder{
-		db:     db,
-		dbPath: dbPath,
-	}
-
-	builder.kafkaReader = kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  []string{kafkaBroker},
-		Topic:    kafkaTopic, // This is the topic the builder consumes from
-		GroupID:  "threatdna-builder-group",
-		MinBytes: 10e3, // 10KB
-		MaxBytes: 10e6, // 10MB
-		MaxAttempts: 10,
-		Dialer: &kafka.Dialer{
-			Timeout:   10 * time.Second,
-			DualStack: true,
-		},
-	})
+		db:          db,
+		dbPath:      dbPath,
+		kafkaBroker: kafkaBroker,
+		kafkaTopic:  kafkaTopic,
+		geoEnricher: geoEnricher,
+	}
+
+	if kafkaBroker == "" || kafkaTopic == "" {
+		return builder, nil
+	}
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_8_0_0
+	// Copartitioning-aware assignment: range keeps a replica's assigned
+	// partitions contiguous, so a future state topic keyed on genome ID
+	// can land on the same replica as the records that built it.
+	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRange
+	// Commit manually, only after BuildGenome+indexGenome succeed, so we
+	// get at-least-once semantics instead of losing messages on crash.
+	config.Consumer.Offsets.AutoCommit.Enable = false
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+
+	group, err := sarama.NewConsumerGroup([]string{kafkaBroker}, builderConsumerGroup, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka consumer group: %w", err)
+	}
+	builder.consumerGroup = group
+
+	producerConfig := sarama.NewConfig()
+	producerConfig.Version = sarama.V2_8_0_0
+	producerConfig.Producer.RequiredAcks = sarama.WaitForLocal
+	producerConfig.Producer.Return.Successes = true
+
+	producer, err := sarama.NewSyncProducer([]string{kafkaBroker}, producerConfig)
+	if err != nil {
+		group.Close()
+		return nil, fmt.Errorf("failed to create genome state producer: %w", err)
+	}
+	builder.genomeProducer = producer
 
 	return builder, nil
 }
 
-// StartKafkaConsumer starts consuming CTI records from Kafka
-func (gb *GenomeBuilder) StartKafkaConsumer(ctx context.Context) {
-	log.Printf("Starting Kafka consumer for topic %s on broker %s", gb.kafkaReader.Config().Topic, gb.kafkaReader.Config().Brokers[0])
+// publishGenomeState emits genome to the compacted threatdna-genomes topic,
+// keyed by genome ID, for Views to materialize. A no-op if the builder was
+// created without Kafka configuration.
+func (gb *GenomeBuilder) publishGenomeState(genome *Genome) error {
+	if gb.genomeProducer == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(genome)
+	if err != nil {
+		return fmt.Errorf("failed to marshal genome %s for state topic: %w", genome.ID, err)
+	}
+
+	_, _, err = gb.genomeProducer.SendMessage(&sarama.ProducerMessage{
+		Topic: genomeStateTopic,
+		Key:   sarama.StringEncoder(genome.ID),
+		Value: sarama.ByteEncoder(payload),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish genome %s to state topic: %w", genome.ID, err)
+	}
+	return nil
+}
 
+// builderConsumerHandler implements sarama.ConsumerGroupHandler, processing
+// claimed messages and committing offsets only once a message's genome has
+// been built and indexed successfully.
+type builderConsumerHandler struct {
+	builder *GenomeBuilder
+}
+
+func (h *builderConsumerHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (h *builderConsumerHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h *builderConsumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	for {
 		select {
-		case <-ctx.Done():
-			log.Println("Kafka consumer stopped.")
-			return
-		default:
-			m, err := gb.kafkaReader.ReadMessage(ctx)
-			if err != nil {
-				log.Printf("Error reading message from Kafka: %v", err)
-				continue
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if err := h.builder.processMessage(message); err != nil {
+				// Returning ends the session without marking this message, so
+				// the rejoin resumes from the last committed offset and the
+				// message is redelivered instead of skipped.
+				log.Printf("Failed Kafka message (partition %d, offset %d), ending session for redelivery: %v", message.Partition, message.Offset, err)
+				return err
 			}
+			session.MarkMessage(message, "")
+			session.Commit()
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
 
-			log.Printf("Received message from Kafka topic %s, partition %d, offset %d: %s",
-				m.Topic, m.Partition, m.Offset, string(m.Value))
+// BuildAndIndex builds a genome from a single CTI record and indexes it,
+// synchronously and without retries. It is the shared core used by the live
+// Kafka consumer (wrapped with retry/backoff in processMessage) and by
+// the in-memory tester package, which drives it directly without Kafka.
+func (gb *GenomeBuilder) BuildAndIndex(record CTIRecord) (*Genome, error) {
+	genome, err := gb.BuildGenome([]CTIRecord{record})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build genome from CTI record %s: %w", record.ID, err)
+	}
+	if err := gb.indexGenome(genome); err != nil {
+		return nil, fmt.Errorf("failed to index genome %s: %w", genome.ID, err)
+	}
+	if err := gb.publishGenomeState(genome); err != nil {
+		return nil, err
+	}
+	return genome, nil
+}
 
-			var record CTIRecord
-			if err := json.Unmarshal(m.Value, &record); err != nil {
-				log.Printf("Failed to unmarshal CTI record from Kafka message: %v", err)
-				continue
+// groupRecordsByActor buckets records for batch genome building, primarily
+// by actor; records with no (or "Unknown") actor fall back to campaign, then
+// source, so every record still lands in some genome candidate group.
+func groupRecordsByActor(records []CTIRecord) map[string][]CTIRecord {
+	groups := make(map[string][]CTIRecord)
+	for _, record := range records {
+		key := record.Actor
+		if key == "" || key == "Unknown" {
+			if record.Campaign != "" {
+				key = "Campaign:" + record.Campaign
+			} else {
+				key = "Source:" + record.Source
 			}
+		}
+		groups[key] = append(groups[key], record)
+	}
+	return groups
+}
 
-			genome, err := gb.BuildGenome([]CTIRecord{record})
-			if err != nil {
-				log.Printf("Failed to build genome from CTI record %s: %v", record.ID, err)
-				continue
-			}
+// BuildGenomesFromRecords groups records into genome candidates (see
+// groupRecordsByActor), then builds and indexes one genome per group,
+// reporting one Increment per group processed via progress — pass
+// NoopProgress{} for silent operation. It is the batch counterpart to
+// BuildAndIndex's one-record-at-a-time Kafka path, for offline backfills
+// from a CTI export.
+func (gb *GenomeBuilder) BuildGenomesFromRecords(records []CTIRecord, progress Progress) ([]*Genome, error) {
+	if progress == nil {
+		progress = NoopProgress{}
+	}
 
-			if err := gb.indexGenome(genome); err != nil {
-				log.Printf("Failed to index genome %s: %v", genome.ID, err)
-				continue
+	groups := groupRecordsByActor(records)
+	progress.Start(len(groups))
+	defer progress.Finish()
+
+	genomes := make([]*Genome, 0, len(groups))
+	for groupKey, groupRecords := range groups {
+		genome, err := gb.BuildGenome(groupRecords)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build genome for group %q: %w", groupKey, err)
+		}
+		if err := gb.indexGenome(genome); err != nil {
+			return nil, fmt.Errorf("failed to index genome %s for group %q: %w", genome.ID, groupKey, err)
+		}
+		genomes = append(genomes, genome)
+		progress.Increment()
+	}
+
+	return genomes, nil
+}
+
+// processMessage builds and indexes the genome for a single Kafka message,
+// retrying transient Bleve/Kafka errors with exponential backoff before
+// giving up (the message is then left uncommitted and redelivered).
+func (gb *GenomeBuilder) processMessage(message *sarama.ConsumerMessage) error {
+	var record CTIRecord
+	if err := json.Unmarshal(message.Value, &record); err != nil {
+		log.Printf("Failed to unmarshal CTI record from Kafka message, skipping: %v", err)
+		return nil
+	}
+
+	const maxAttempts = 5
+	backoff := 500 * time.Millisecond
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		genome, err := gb.BuildAndIndex(record)
+		if err == nil {
+			log.Printf("Successfully processed and indexed genome %s from Kafka message (partition %d, offset %d)",
+				genome.ID, message.Partition, message.Offset)
+			return nil
+		}
+		lastErr = err
+		log.Printf("Transient error processing CTI record %s (attempt %d/%d): %v. Retrying in %v...",
+			record.ID, attempt, maxAttempts, lastErr, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", maxAttempts, lastErr)
+}
+
+// StartKafkaConsumer joins the builder consumer group and consumes CTI
+// records until ctx is cancelled. Multiple builder replicas can call this
+// concurrently against the same broker/topic and have partitions rebalanced
+// automatically across them.
+func (gb *GenomeBuilder) StartKafkaConsumer(ctx context.Context) {
+	if gb.consumerGroup == nil {
+		log.Println("No Kafka consumer group configured, skipping consumer startup.")
+		return
+	}
+
+	log.Printf("Starting Kafka consumer group %s for topic %s", builderConsumerGroup, gb.kafkaTopic)
+	handler := &builderConsumerHandler{builder: gb}
+
+	go func() {
+		for {
+			if err := gb.consumerGroup.Consume(ctx, []string{gb.kafkaTopic}, handler); err != nil {
+				log.Printf("Error from Kafka consumer group: %v", err)
+			}
+			if ctx.Err() != nil {
+				log.Println("Kafka consumer stopped.")
+				return
 			}
-			log.Printf("Successfully processed and indexed genome %s from Kafka message", genome.ID)
 		}
+	}()
+
+	go func() {
+		for err := range gb.consumerGroup.Errors() {
+			log.Printf("Kafka consumer group error: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+}
+
+// buildGenomeParallelThreshold is the record count above which BuildGenome
+// fans aggregation out across a worker pool instead of folding records in
+// one goroutine; below it, the parallelism overhead isn't worth paying.
+const buildGenomeParallelThreshold = 100
+
+// genomeAggregate accumulates the per-record data BuildGenome folds into a
+// Genome. mergeLocal is safe to call from a single goroutine without
+// locking; mergeFrom folds another (worker-local) aggregate into this one
+// under mu, so parallel aggregation only takes the lock once per worker
+// instead of once per record.
+type genomeAggregate struct {
+	mu sync.Mutex
+
+	sourceIDs    map[string]bool
+	platforms    map[string]bool
+	cves         map[string]bool
+	enrichedCVEs map[string]EnrichedCVE
+	detections   map[string]bool
+	countries    map[string]bool
+	asns         map[string]bool
+	iocs         map[string]IOC
+	allTTPs      []TTP
+	totalIOCs    int
+	firstSeen    time.Time
+	lastSeen     time.Time
+	sourceText   strings.Builder
+}
+
+func newGenomeAggregate() *genomeAggregate {
+	return &genomeAggregate{
+		sourceIDs:    make(map[string]bool),
+		platforms:    make(map[string]bool),
+		cves:         make(map[string]bool),
+		enrichedCVEs: make(map[string]EnrichedCVE),
+		detections:   make(map[string]bool),
+		countries:    make(map[string]bool),
+		asns:         make(map[string]bool),
+		iocs:         make(map[string]IOC),
+	}
+}
+
+// mergeLocal folds one CTI record into the aggregate. Not goroutine-safe on
+// its own; callers either own the aggregate exclusively (the sequential
+// path) or merge a worker-local aggregate into the shared one afterward via
+// mergeFrom (the parallel path).
+func (a *genomeAggregate) mergeLocal(record CTIRecord) {
+	a.sourceIDs[record.ID] = true
+
+	if a.firstSeen.IsZero() || record.Date.Before(a.firstSeen) {
+		a.firstSeen = record.Date
+	}
+	if a.lastSeen.IsZero() || record.Date.After(a.lastSeen) {
+		a.lastSeen = record.Date
+	}
+
+	for _, tag := range record.Tags {
+		if strings.HasPrefix(strings.ToUpper(tag), "CVE-") {
+			a.cves[tag] = true
+		} else if isValidPlatform(tag) {
+			a.platforms[tag] = true
+		}
+	}
+
+	for _, cve := range record.CVEs {
+		a.cves[cve.ID] = true
+		a.enrichedCVEs[cve.ID] = cve
 	}
+
+	for _, hit := range record.Detections {
+		a.detections[hit.TemplateID] = true
+	}
+
+	for _, ioc := range record.IOCs {
+		if country := ioc.Enrichment["country"]; country != "" {
+			a.countries[country] = true
+		}
+		if asn := ioc.Enrichment["asn"]; asn != "" {
+			a.asns[asn] = true
+		}
+		a.iocs[ioc.Type+":"+ioc.Value] = ioc
+	}
+
+	a.allTTPs = append(a.allTTPs, record.TTPs...)
+	a.totalIOCs += len(record.IOCs)
+
+	a.sourceText.WriteString(record.RawText)
+	a.sourceText.WriteString("\n")
+}
+
+// mergeFrom folds a worker-local aggregate into a, taking a's lock once for
+// the whole merge rather than once per record.
+func (a *genomeAggregate) mergeFrom(local *genomeAggregate) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for id := range local.sourceIDs {
+		a.sourceIDs[id] = true
+	}
+	for p := range local.platforms {
+		a.platforms[p] = true
+	}
+	for c := range local.cves {
+		a.cves[c] = true
+	}
+	for id, cve := range local.enrichedCVEs {
+		a.enrichedCVEs[id] = cve
+	}
+	for id := range local.detections {
+		a.detections[id] = true
+	}
+	for country := range local.countries {
+		a.countries[country] = true
+	}
+	for asn := range local.asns {
+		a.asns[asn] = true
+	}
+	for key, ioc := range local.iocs {
+		a.iocs[key] = ioc
+	}
+	a.allTTPs = append(a.allTTPs, local.allTTPs...)
+	a.totalIOCs += local.totalIOCs
+	if a.firstSeen.IsZero() || (!local.firstSeen.IsZero() && local.firstSeen.Before(a.firstSeen)) {
+		a.firstSeen = local.firstSeen
+	}
+	if local.lastSeen.After(a.lastSeen) {
+		a.lastSeen = local.lastSeen
+	}
+	a.sourceText.WriteString(local.sourceText.String())
+}
+
+// aggregateRecordsParallel splits records across runtime.NumCPU() workers,
+// each folding its chunk into a local aggregate before merging once into
+// agg, so the shared mutex is only taken len(workers) times total.
+func aggregateRecordsParallel(records []CTIRecord, agg *genomeAggregate) {
+	workers := runtime.NumCPU()
+	if workers > len(records) {
+		workers = len(records)
+	}
+	chunkSize := (len(records) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(records); start += chunkSize {
+		end := start + chunkSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		wg.Add(1)
+		go func(chunk []CTIRecord) {
+			defer wg.Done()
+			local := newGenomeAggregate()
+			for _, record := range chunk {
+				local.mergeLocal(record)
+			}
+			agg.mergeFrom(local)
+		}(records[start:end])
+	}
+	wg.Wait()
+}
+
+// ttpTacticPairs sorts ttps and tactics together by technique ID, preserving
+// the i-th ttp's correspondence with the i-th tactic.
+type ttpTacticPairs struct {
+	ttps    []string
+	tactics []string
+}
+
+func (p *ttpTacticPairs) Len() int           { return len(p.ttps) }
+func (p *ttpTacticPairs) Less(i, j int) bool { return p.ttps[i] < p.ttps[j] }
+func (p *ttpTacticPairs) Swap(i, j int) {
+	p.ttps[i], p.ttps[j] = p.ttps[j], p.ttps[i]
+	p.tactics[i], p.tactics[j] = p.tactics[j], p.tactics[i]
 }
 
 // BuildGenome creates a genome from one or more CTI records
@@ -116,43 +512,49 @@ func (gb *GenomeBuilder) BuildGenome(records []CTIRecord) (*Genome, error) {
 
 	log.Printf("Building genome from %d CTI records", len(records))
 
-	// Aggregate data from all records
-	sourceIDs := make(map[string]bool)
-	platforms := make(map[string]bool)
-	cves := make(map[string]bool)
-	var firstSeen, lastSeen time.Time
-	var totalConfidence float64
-	var allTTPs []TTP
-	totalIOCs := 0
-
-	var allSourceTextBuilder strings.Builder
-	for _, record := range records {
-		sourceIDs[record.ID] = true
+	// Resolve country/ASN metadata for IP-type IOCs before aggregating, so
+	// genomeAggregate.mergeLocal sees each record's IOCs already enriched.
+	for i := range records {
+		gb.EnrichIOCs(records[i].IOCs)
+	}
 
-		// Track dates
-		if firstSeen.IsZero() || record.Date.Before(firstSeen) {
-			firstSeen = record.Date
-		}
-		if lastSeen.IsZero() || record.Date.After(lastSeen) {
-			lastSeen = record.Date
+	// Aggregate data from all records. Large inputs fan out across a bounded
+	// worker pool, each owning its own local maps to avoid lock contention;
+	// small inputs aren't worth the goroutine overhead and run inline.
+	agg := newGenomeAggregate()
+	if len(records) > buildGenomeParallelThreshold {
+		aggregateRecordsParallel(records, agg)
+	} else {
+		for _, record := range records {
+			agg.mergeLocal(record)
 		}
+	}
 
-		// Extract platforms and CVEs from tags
-		for _, tag := range record.Tags {
-			if strings.HasPrefix(strings.ToUpper(tag), "CVE-") {
-				cves[tag] = true
-			} else if isValidPlatform(tag) {
-				platforms[tag] = true
-			}
-		}
+	sourceIDs := agg.sourceIDs
+	platforms := agg.platforms
+	cves := agg.cves
+	enrichedCVEs := agg.enrichedCVEs
+	detections := agg.detections
+	firstSeen := agg.firstSeen
+	lastSeen := agg.lastSeen
+	allTTPs := agg.allTTPs
+	totalIOCs := agg.totalIOCs
+	var totalConfidence float64
 
-		// Collect TTPs
-		allTTPs = append(allTTPs, record.TTPs...)
-		totalIOCs += len(record.IOCs)
+	allSourceText := agg.sourceText.String()
 
-		// Aggregate RawText
-		allSourceTextBuilder.WriteString(record.RawText)
-		allSourceTextBuilder.WriteString("\n") // Add a newline for separation
+	// Capture the TTPs in ingestion order (deduped, first occurrence wins)
+	// before they get confidence/alpha-sorted below. This is what the
+	// behavioral-sequence search field is built from, since the sorted
+	// ttps slice no longer reflects the order techniques were observed in.
+	var ttpSequence []string
+	seenSequence := make(map[string]bool)
+	for _, ttp := range allTTPs {
+		if seenSequence[ttp.TechniqueID] {
+			continue
+		}
+		seenSequence[ttp.TechniqueID] = true
+		ttpSequence = append(ttpSequence, ttp.TechniqueID)
 	}
 
 	// Build the ordered sequence, removing duplicates
@@ -160,9 +562,14 @@ func (gb *GenomeBuilder) BuildGenome(records []CTIRecord) (*Genome, error) {
 	var tactics []string
 	seenTTPs := make(map[string]bool)
 
-	// Sort TTPs by confidence (highest first)
+	// Sort TTPs by confidence (highest first), breaking ties on technique ID
+	// so the dedup pass below picks the same "first occurrence" regardless
+	// of the order the parallel aggregation pass appended them in.
 	sort.Slice(allTTPs, func(i, j int) bool {
-		return allTTPs[i].Confidence > allTTPs[j].Confidence
+		if allTTPs[i].Confidence != allTTPs[j].Confidence {
+			return allTTPs[i].Confidence > allTTPs[j].Confidence
+		}
+		return allTTPs[i].TechniqueID < allTTPs[j].TechniqueID
 	})
 
 	for _, ttp := range allTTPs {
@@ -185,6 +592,11 @@ func (gb *GenomeBuilder) BuildGenome(records []CTIRecord) (*Genome, error) {
 		}
 	}
 
+	// Sort the final slices together, keeping each ttps[i]/tactics[i] pair
+	// intact, so a genome built from a parallel aggregation pass serializes
+	// identically to one built sequentially.
+	sort.Sort(&ttpTacticPairs{ttps: ttps, tactics: tactics})
+
 	// Determine primary actor and campaign
 	actor, campaign := determineActorAndCampaign(records)
 
@@ -204,11 +616,56 @@ func (gb *GenomeBuilder) BuildGenome(records []CTIRecord) (*Genome, error) {
 		cveList = append(cveList, cve)
 	}
 
-	// Calculate overall confidence
+	detectionList := make([]string, 0, len(detections))
+	for id := range detections {
+		detectionList = append(detectionList, id)
+	}
+
+	countryList := make([]string, 0, len(agg.countries))
+	for country := range agg.countries {
+		countryList = append(countryList, country)
+	}
+	sort.Strings(countryList)
+
+	asnList := make([]string, 0, len(agg.asns))
+	for asn := range agg.asns {
+		asnList = append(asnList, asn)
+	}
+	sort.Strings(asnList)
+
+	iocList := make([]IOC, 0, len(agg.iocs))
+	for _, ioc := range agg.iocs {
+		iocList = append(iocList, ioc)
+	}
+	sort.Slice(iocList, func(i, j int) bool {
+		if iocList[i].Type != iocList[j].Type {
+			return iocList[i].Type < iocList[j].Type
+		}
+		return iocList[i].Value < iocList[j].Value
+	})
+
+	maxCVSS := 0.0
+	kevExploited := false
+	for _, cve := range enrichedCVEs {
+		if cve.CVSSScore > maxCVSS {
+			maxCVSS = cve.CVSSScore
+		}
+		if cve.Exploited {
+			kevExploited = true
+		}
+	}
+
+	// Calculate overall confidence from TTP agreement, then weight in CVE
+	// severity: a genome tied to a critical, actively-exploited CVE should
+	// rank above one with identical TTPs but no known vulnerability.
 	avgConfidence := 0.0
 	if len(ttps) > 0 {
 		avgConfidence = totalConfidence / float64(len(ttps))
 	}
+	confidence := avgConfidence
+	if len(enrichedCVEs) > 0 {
+		confidence = 0.6*avgConfidence + 0.4*(maxCVSS/10.0)
+	}
 
 	// Create genome
 	genome := &Genome{
@@ -220,16 +677,26 @@ func (gb *GenomeBuilder) BuildGenome(records []CTIRecord) (*Genome, error) {
 		Tactics:     tactics,
 		Platforms:   platformList,
 		CVEs:        cveList,
+		Detections:  detectionList,
 		FirstSeen:   firstSeen,
 		LastSeen:    lastSeen,
-		Confidence:  avgConfidence,
+		Confidence:  confidence,
 		SourceCount: len(records),
 		IOCCount:    totalIOCs,
-		AllSourceText: allSourceTextBuilder.String(),
+		AllSourceText: allSourceText,
+		MaxCVSSScore: maxCVSS,
+		KEVExploited: kevExploited,
 		Metadata: map[string]interface{}{
-			"build_time": time.Now(),
-			"ttp_count":  len(ttps),
+			"build_time":     time.Now(),
+			"ttp_count":      len(ttps),
 			"unique_tactics": len(RemoveDuplicates(tactics)),
+			"max_cvss":       maxCVSS,
+			"kev_exploited":  kevExploited,
+			"cves_enriched":  enrichedCVEs,
+			"geo_countries":  countryList,
+			"geo_asns":       asnList,
+			"iocs":           iocList,
+			"ttp_sequence":   ttpSequence,
 		},
 	}
 
@@ -241,6 +708,18 @@ func (gb *GenomeBuilder) BuildGenome(records []CTIRecord) (*Genome, error) {
 
 // indexGenome enriches and indexes the document.
 func (gb *GenomeBuilder) indexGenome(genome *Genome) error {
+	maxCVSS, _ := genome.Metadata["max_cvss"].(float64)
+	kevExploited, _ := genome.Metadata["kev_exploited"].(bool)
+	geoCountries, _ := genome.Metadata["geo_countries"].([]string)
+	geoASNs, _ := genome.Metadata["geo_asns"].([]string)
+	ttpSequence, _ := genome.Metadata["ttp_sequence"].([]string)
+	if ttpSequence == nil {
+		// Metadata wasn't populated by BuildGenome (e.g. a genome loaded
+		// from storage some other way) — fall back to the sorted TTPs,
+		// which at least yields a stable, if not truly ordered, sequence.
+		ttpSequence = genome.TTPs
+	}
+
 	searchDoc := SearchDocument{
 		Actor:         genome.Actor,
 		Campaign:      genome.Campaign,
@@ -252,9 +731,377 @@ func (gb *GenomeBuilder) indexGenome(genome *Genome) error {
 		LastSeen:      genome.LastSeen,
 		AllSourceText: genome.AllSourceText,
 		Type:          "genome",
+		CVEIDs:        genome.CVEs,
+		MaxCVSSScore:  maxCVSS,
+		KEVExploited:  kevExploited,
+		Detections:    genome.Detections,
+		TTPSequence:   strings.Join(ttpSequence, " "),
+		GeoCountries:  geoCountries,
+		GeoASNs:       geoASNs,
+	}
+
+	if err := gb.db.Index(genome.ID, searchDoc); err != nil {
+		return err
+	}
+
+	if gb.cache != nil {
+		if err := gb.cache.PutGenome(genome); err != nil {
+			log.Printf("⚠️  Failed to write genome %s through to redis cache: %v", genome.ID, err)
+		}
+	}
+	return nil
+}
+
+// GetGenome returns the genome with the given ID, checking the Redis
+// cache first (when configured) before falling back to Bleve.
+func (gb *GenomeBuilder) GetGenome(id string) (*Genome, error) {
+	if gb.cache != nil {
+		if genome, ok := gb.cache.GetGenome(id); ok {
+			return genome, nil
+		}
+	}
+
+	request := bleve.NewSearchRequest(bleve.NewDocIDQuery([]string{id}))
+	request.Fields = []string{"*"}
+	results, err := gb.db.Search(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up genome %s: %w", id, err)
+	}
+	if len(results.Hits) == 0 {
+		return nil, fmt.Errorf("genome %s not found", id)
+	}
+	genome := genomeFromFields(id, results.Hits[0].Fields)
+
+	if gb.cache != nil {
+		if err := gb.cache.PutGenome(genome); err != nil {
+			log.Printf("⚠️  Failed to cache genome %s after a store read: %v", id, err)
+		}
+	}
+	return genome, nil
+}
+
+// ListGenomes returns the genomes stored in the Bleve index, optionally
+// filtered by actor/platform substring, reconstructed from the indexed
+// SearchDocuments. limit <= 0 means no limit.
+func (gb *GenomeBuilder) ListGenomes(actor, platform string, limit int) ([]*Genome, error) {
+	query := bleve.NewMatchAllQuery()
+	request := bleve.NewSearchRequest(query)
+	request.Fields = []string{"*"}
+	request.Size = limit
+	if request.Size <= 0 {
+		count, err := gb.db.DocCount()
+		if err != nil {
+			return nil, fmt.Errorf("failed to count genomes: %w", err)
+		}
+		request.Size = int(count)
+	}
+
+	results, err := gb.db.Search(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list genomes: %w", err)
+	}
+
+	var genomes []*Genome
+	for _, hit := range results.Hits {
+		genome := genomeFromFields(hit.ID, hit.Fields)
+
+		if actor != "" && !strings.Contains(strings.ToLower(genome.Actor), strings.ToLower(actor)) {
+			continue
+		}
+		if platform != "" {
+			found := false
+			for _, p := range genome.Platforms {
+				if strings.Contains(strings.ToLower(p), strings.ToLower(platform)) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+
+		genomes = append(genomes, genome)
+	}
+
+	return genomes, nil
+}
+
+// genomeFromFields reconstructs a Genome from the stored fields of a
+// SearchDocument hit.
+func genomeFromFields(id string, fields map[string]interface{}) *Genome {
+	return &Genome{
+		ID:         id,
+		Actor:      fieldString(fields["actor"]),
+		Campaign:   fieldString(fields["campaign"]),
+		TTPs:       fieldStrings(fields["ttps"]),
+		Tactics:    fieldStrings(fields["tactics"]),
+		Platforms:  fieldStrings(fields["platforms"]),
+		CVEs:       fieldStrings(fields["cve_id"]),
+		Detections: fieldStrings(fields["detections"]),
+		Confidence: fieldFloat(fields["confidence"]),
+		FirstSeen:  fieldTime(fields["first_seen"]),
+		LastSeen:   fieldTime(fields["last_seen"]),
+		MaxCVSSScore: fieldFloat(fields["cvss_score"]),
+		KEVExploited: fieldBool(fields["kev_exploited"]),
+	}
+}
+
+// topTTPLimit bounds how many distinct TTPs GetGenomeStats ranks, so a
+// long-tail index of rare techniques doesn't blow up the facet response.
+const topTTPLimit = 10
+
+// GenomeStats is the JSON contract served at /stats: a snapshot suitable
+// for dashboards or a Prometheus/Grafana scrape, without shelling out to
+// count Bleve documents by hand.
+type GenomeStats struct {
+	TotalGenomes     int            `json:"total_genomes"`
+	ByActor          map[string]int `json:"by_actor"`
+	ByCampaign       map[string]int `json:"by_campaign"`
+	ByPlatform       map[string]int `json:"by_platform"`
+	ByTactic         map[string]int `json:"by_tactic"`
+	TopTTPs          []TTPCount     `json:"top_ttps"`
+	MinConfidence    float64        `json:"min_confidence"`
+	MaxConfidence    float64        `json:"max_confidence"`
+	AvgConfidence    float64        `json:"avg_confidence"`
+	AvgGenomeLength  float64        `json:"avg_genome_length"`
+	FirstSeenBuckets map[string]int `json:"first_seen_buckets"`
+	LastSeenBuckets  map[string]int `json:"last_seen_buckets"`
+	SeverityFrequency map[string]int `json:"severity_frequency"`
+}
+
+// TTPCount is one entry of GenomeStats.TopTTPs: a technique ID and how many
+// genomes reference it.
+type TTPCount struct {
+	TechniqueID string `json:"technique_id"`
+	Count       int    `json:"count"`
+}
+
+// GetGenomeStats runs Bleve facet queries for the categorical breakdowns
+// (by actor/platform/tactic, top TTPs) and a full scan of genome fields for
+// the numeric confidence range/average and first/last-seen histograms,
+// which Bleve's facet API has no native aggregation for.
+func (gb *GenomeBuilder) GetGenomeStats() (*GenomeStats, error) {
+	facetRequest := bleve.NewSearchRequest(bleve.NewMatchAllQuery())
+	facetRequest.Size = 0
+	facetRequest.AddFacet("by_actor", bleve.NewFacetRequest("actor", 1000))
+	facetRequest.AddFacet("by_campaign", bleve.NewFacetRequest("campaign", 1000))
+	facetRequest.AddFacet("by_platform", bleve.NewFacetRequest("platforms", 1000))
+	facetRequest.AddFacet("by_tactic", bleve.NewFacetRequest("tactics", 1000))
+	facetRequest.AddFacet("top_ttps", bleve.NewFacetRequest("ttps", topTTPLimit))
+
+	facetResults, err := gb.db.Search(facetRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run genome stats facet query: %w", err)
+	}
+
+	stats := &GenomeStats{
+		TotalGenomes: int(facetResults.Total),
+		ByActor:      facetTermCounts(facetResults.Facets["by_actor"]),
+		ByCampaign:   facetTermCounts(facetResults.Facets["by_campaign"]),
+		ByPlatform:   facetTermCounts(facetResults.Facets["by_platform"]),
+		ByTactic:     facetTermCounts(facetResults.Facets["by_tactic"]),
+		TopTTPs:      facetTermList(facetResults.Facets["top_ttps"]),
+	}
+
+	genomes, err := gb.ListGenomes("", "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list genomes for stats: %w", err)
+	}
+
+	stats.FirstSeenBuckets = make(map[string]int)
+	stats.LastSeenBuckets = make(map[string]int)
+	stats.SeverityFrequency = make(map[string]int)
+	if len(genomes) > 0 {
+		stats.MinConfidence = genomes[0].Confidence
+		var totalConfidence float64
+		var totalTTPs int
+		for _, genome := range genomes {
+			if genome.Confidence < stats.MinConfidence {
+				stats.MinConfidence = genome.Confidence
+			}
+			if genome.Confidence > stats.MaxConfidence {
+				stats.MaxConfidence = genome.Confidence
+			}
+			totalConfidence += genome.Confidence
+			totalTTPs += len(genome.TTPs)
+
+			if !genome.FirstSeen.IsZero() {
+				stats.FirstSeenBuckets[genome.FirstSeen.Format("2006-01")]++
+			}
+			if !genome.LastSeen.IsZero() {
+				stats.LastSeenBuckets[genome.LastSeen.Format("2006-01")]++
+			}
+
+			_, bucket := GenomeSeverity(genome)
+			stats.SeverityFrequency[bucket]++
+		}
+		stats.AvgConfidence = totalConfidence / float64(len(genomes))
+		stats.AvgGenomeLength = float64(totalTTPs) / float64(len(genomes))
+	}
+
+	return stats, nil
+}
+
+// facetTermCounts flattens a Bleve term facet result into a term->count map.
+func facetTermCounts(facetResult *search.FacetResult) map[string]int {
+	counts := make(map[string]int)
+	if facetResult == nil {
+		return counts
+	}
+	for _, term := range facetResult.Terms.Terms() {
+		counts[term.Term] = term.Count
+	}
+	return counts
+}
+
+// facetTermList flattens a Bleve term facet result into an ordered
+// (already-sorted-by-count) list, for facets where rank matters.
+func facetTermList(facetResult *search.FacetResult) []TTPCount {
+	var out []TTPCount
+	if facetResult == nil {
+		return out
+	}
+	for _, term := range facetResult.Terms.Terms() {
+		out = append(out, TTPCount{TechniqueID: term.Term, Count: term.Count})
+	}
+	return out
+}
+
+// HealthStatus is the JSON contract served at /healthz: readiness reflects
+// whether the Bleve index is still open and how far the builder's consumer
+// group has fallen behind the CTI topic.
+type HealthStatus struct {
+	Status      string `json:"status"`
+	BleveOpen   bool   `json:"bleve_open"`
+	ConsumerLag int64  `json:"consumer_lag"`
+	Error       string `json:"error,omitempty"`
+}
+
+// IndexSize returns the number of documents currently stored in the Bleve
+// index, for operator-facing surfaces like the dashboard's /metrics.
+func (gb *GenomeBuilder) IndexSize() (uint64, error) {
+	return gb.db.DocCount()
+}
+
+// GetHealth reports Bleve index openness and Kafka consumer lag. Status is
+// "degraded" if either check fails; callers (e.g. the /healthz handler)
+// decide what HTTP status that maps to.
+func (gb *GenomeBuilder) GetHealth() *HealthStatus {
+	health := &HealthStatus{Status: "ok"}
+
+	if _, err := gb.db.DocCount(); err != nil {
+		health.Status = "degraded"
+		health.Error = fmt.Sprintf("bleve index unavailable: %v", err)
+		return health
+	}
+	health.BleveOpen = true
+
+	lag, err := gb.GetConsumerLag()
+	if err != nil {
+		health.Status = "degraded"
+		health.Error = fmt.Sprintf("failed to compute consumer lag: %v", err)
+		return health
+	}
+	health.ConsumerLag = lag
+
+	return health
+}
+
+// GetConsumerLag sums, across every partition of the builder's CTI topic,
+// the gap between each partition's latest offset and the builder consumer
+// group's last committed (OffsetsCommit) offset. Returns 0 without error if
+// the builder was created without Kafka configuration.
+func (gb *GenomeBuilder) GetConsumerLag() (int64, error) {
+	if gb.kafkaBroker == "" || gb.kafkaTopic == "" {
+		return 0, nil
+	}
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_8_0_0
+
+	client, err := sarama.NewClient([]string{gb.kafkaBroker}, config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to Kafka for lag check: %w", err)
+	}
+	defer client.Close()
+
+	partitions, err := client.Partitions(gb.kafkaTopic)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list partitions for topic %s: %w", gb.kafkaTopic, err)
+	}
+
+	admin, err := sarama.NewClusterAdmin([]string{gb.kafkaBroker}, config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create cluster admin for lag check: %w", err)
 	}
+	defer admin.Close()
 
-	return gb.db.Index(genome.ID, searchDoc)
+	committed, err := admin.ListConsumerGroupOffsets(builderConsumerGroup, map[string][]int32{gb.kafkaTopic: partitions})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch committed offsets for group %s: %w", builderConsumerGroup, err)
+	}
+
+	var lag int64
+	for _, partition := range partitions {
+		latest, err := client.GetOffset(gb.kafkaTopic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get latest offset for partition %d: %w", partition, err)
+		}
+
+		block := committed.GetBlock(gb.kafkaTopic, partition)
+		if block == nil || block.Offset < 0 {
+			continue
+		}
+		if partitionLag := latest - block.Offset; partitionLag > 0 {
+			lag += partitionLag
+		}
+	}
+	return lag, nil
+}
+
+func fieldString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func fieldFloat(v interface{}) float64 {
+	f, _ := v.(float64)
+	return f
+}
+
+func fieldStrings(v interface{}) []string {
+	switch vals := v.(type) {
+	case []interface{}:
+		out := make([]string, 0, len(vals))
+		for _, val := range vals {
+			if s, ok := val.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		return []string{vals}
+	default:
+		return nil
+	}
+}
+
+func fieldBool(v interface{}) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+func fieldTime(v interface{}) time.Time {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
 }
 
 // Close closes the Bleve database and Kafka connections.
@@ -264,9 +1111,19 @@ func (gb *GenomeBuilder) Close() error {
 			log.Printf("Error closing Bleve database: %v", err)
 		}
 	}
-	if gb.kafkaReader != nil {
-		if err := gb.kafkaReader.Close(); err != nil {
-			log.Printf("Error closing Kafka reader: %v", err)
+	if gb.consumerGroup != nil {
+		if err := gb.consumerGroup.Close(); err != nil {
+			log.Printf("Error closing Kafka consumer group: %v", err)
+		}
+	}
+	if gb.genomeProducer != nil {
+		if err := gb.genomeProducer.Close(); err != nil {
+			log.Printf("Error closing genome state producer: %v", err)
+		}
+	}
+	if gb.geoEnricher != nil {
+		if err := gb.geoEnricher.Close(); err != nil {
+			log.Printf("Error closing GeoIP enricher: %v", err)
 		}
 	}
 	return nil
@@ -341,25 +1198,30 @@ func RemoveDuplicates(items []string) []string {
 	return result
 }
 
-// SearchDocument is the enriched document we will store in the Bleve index.
-type SearchDocument struct {
-	Actor           string    `json:"actor"`
-	Campaign        string    `json:"campaign"`
-	TTPs            []string  `json:"ttps"`
-	Tactics         []string  `json:"tactics"`
-	Platforms       []string  `json:"platforms"`
-	Confidence      float64   `json:"confidence"`
-	FirstSeen       time.Time `json:"first_seen"`
-	LastSeen        time.Time `json:"last_seen"`
-	AllSourceText   string    `json:"all_source_text"`
-	Type            string    `json:"type"`
-}
+// ttpSequenceAnalyzer is the name of the custom analyzer registered on the
+// ttp_sequence field: a whitespace tokenizer feeding a shingle filter that
+// emits bigrams and trigrams of technique IDs, preserving their order.
+const ttpSequenceAnalyzer = "ttp_sequence"
 
 // createIndex builds and returns a new Bleve index with the correct mapping.
 func CreateBleveIndexMapping() *mapping.IndexMappingImpl {
 	keywordFieldMapping := bleve.NewKeywordFieldMapping()
 	testFieldMapping := bleve.NewTextFieldMapping()
 
+	indexMapping := bleve.NewIndexMapping()
+	// bleve resolves TypeField via reflection on the Go struct field name,
+	// not its JSON tag, so this must be "Type" (SearchDocument's field),
+	// not "type" (its json tag) — get this wrong and every document falls
+	// through to the dynamic default mapping, silently skipping the
+	// "genome" docMapping below (including the ttp_sequence analyzer).
+	indexMapping.TypeField = "Type"
+	if err := addTTPSequenceAnalyzer(indexMapping); err != nil {
+		log.Printf("⚠️  Failed to register ttp_sequence analyzer, sequence search will be unavailable: %v", err)
+	}
+
+	ttpSequenceFieldMapping := bleve.NewTextFieldMapping()
+	ttpSequenceFieldMapping.Analyzer = ttpSequenceAnalyzer
+
 	docMapping := bleve.NewDocumentMapping()
 	docMapping.AddFieldMappingsAt("actor", keywordFieldMapping)
 	docMapping.AddFieldMappingsAt("campaign", keywordFieldMapping)
@@ -367,9 +1229,177 @@ func CreateBleveIndexMapping() *mapping.IndexMappingImpl {
 	docMapping.AddFieldMappingsAt("tactics", keywordFieldMapping)
 	docMapping.AddFieldMappingsAt("platforms", keywordFieldMapping)
 	docMapping.AddFieldMappingsAt("all_source_text", testFieldMapping)
+	docMapping.AddFieldMappingsAt("cve_id", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("cvss_score", bleve.NewNumericFieldMapping())
+	docMapping.AddFieldMappingsAt("kev_exploited", bleve.NewBooleanFieldMapping())
+	docMapping.AddFieldMappingsAt("detections", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("ttp_sequence", ttpSequenceFieldMapping)
 
-	indexMapping := bleve.NewIndexMapping()
 	indexMapping.AddDocumentMapping("genome", docMapping)
 
 	return indexMapping
 }
+
+// addTTPSequenceAnalyzer registers the whitespace+shingle analyzer used by
+// the ttp_sequence field onto mapping.
+func addTTPSequenceAnalyzer(indexMapping *mapping.IndexMappingImpl) error {
+	if err := indexMapping.AddCustomTokenFilter("ttp_shingle", map[string]interface{}{
+		"type":            shingle.Name,
+		"min":             2.0,
+		"max":             3.0,
+		"output_original": true,
+	}); err != nil {
+		return fmt.Errorf("failed to register ttp_shingle token filter: %w", err)
+	}
+
+	if err := indexMapping.AddCustomAnalyzer(ttpSequenceAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     whitespace.Name,
+		"token_filters": []string{"ttp_shingle"},
+	}); err != nil {
+		return fmt.Errorf("failed to register %s analyzer: %w", ttpSequenceAnalyzer, err)
+	}
+	return nil
+}
+
+// GenomeHit is a single scored result from RunSequenceSearch.
+type GenomeHit struct {
+	ID       string  `json:"id"`
+	Actor    string  `json:"actor,omitempty"`
+	Campaign string  `json:"campaign,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// techniqueIDPattern recognizes MITRE ATT&CK technique IDs (T1003,
+// T1003.001) within a sequence query, so RunSequenceSearch can split a
+// mixed query like ["APT29", "T1003", "T1071"] into TTP chain tokens and
+// actor keyword tokens.
+var techniqueIDPattern = regexp.MustCompile(`(?i)^T\d{4}(\.\d{3})?$`)
+
+// RunSequenceSearch finds genomes whose TTP sequence matches the ordered
+// technique chain in sequence (e.g. "T1078 -> T1053 -> T1547"). TTP-shaped
+// tokens become a MatchPhraseQuery (with the given fuzziness, bleve's only
+// knob for approximate phrase matching — there is no separate slop concept
+// in bleve v2) against the shingled ttp_sequence field; any other tokens
+// (e.g. an actor name) become boosted keyword matches against the actor
+// field, combined via a DisjunctionQuery so "APT29 doing credential
+// dumping -> lateral movement" ranks genomes from that actor higher
+// without excluding other matches.
+func RunSequenceSearch(indexPath string, sequence []string, fuzziness int) ([]GenomeHit, error) {
+	index, err := bleve.Open(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index %s: %w", indexPath, err)
+	}
+	defer index.Close()
+
+	var ttps, keywords []string
+	for _, token := range sequence {
+		if techniqueIDPattern.MatchString(token) {
+			ttps = append(ttps, token)
+		} else if token != "" {
+			keywords = append(keywords, token)
+		}
+	}
+	if len(ttps) == 0 {
+		return nil, fmt.Errorf("sequence must contain at least one technique ID")
+	}
+
+	phrase := bleve.NewMatchPhraseQuery(strings.Join(ttps, " "))
+	phrase.SetField("ttp_sequence")
+	phrase.SetFuzziness(fuzziness)
+
+	disjuncts := []bleveQuery.Query{phrase}
+	for _, keyword := range keywords {
+		actorQuery := bleve.NewMatchQuery(keyword)
+		actorQuery.SetField("actor")
+		actorQuery.SetBoost(2.0)
+		disjuncts = append(disjuncts, actorQuery)
+	}
+
+	searchQuery := bleve.NewDisjunctionQuery(disjuncts...)
+	request := bleve.NewSearchRequest(searchQuery)
+	request.Fields = []string{"actor", "campaign"}
+	request.Size = 25
+
+	results, err := index.Search(request)
+	if err != nil {
+		return nil, fmt.Errorf("sequence search failed: %w", err)
+	}
+
+	hits := make([]GenomeHit, 0, len(results.Hits))
+	for _, hit := range results.Hits {
+		hits = append(hits, GenomeHit{
+			ID:       hit.ID,
+			Actor:    fieldString(hit.Fields["actor"]),
+			Campaign: fieldString(hit.Fields["campaign"]),
+			Score:    hit.Score,
+		})
+	}
+	return hits, nil
+}
+
+// BackfillTTPSequenceField re-indexes every existing genome document in
+// indexPath so it gets the ttp_sequence field retrofitted, for indices
+// built before behavioral-sequence search was added.
+func BackfillTTPSequenceField(indexPath string) error {
+	index, err := bleve.Open(indexPath)
+	if err != nil {
+		return fmt.Errorf("failed to open index %s: %w", indexPath, err)
+	}
+	defer index.Close()
+
+	count, err := index.DocCount()
+	if err != nil {
+		return fmt.Errorf("failed to count documents for backfill: %w", err)
+	}
+
+	request := bleve.NewSearchRequest(bleve.NewMatchAllQuery())
+	request.Fields = []string{"*"}
+	request.Size = int(count)
+
+	results, err := index.Search(request)
+	if err != nil {
+		return fmt.Errorf("failed to list documents for backfill: %w", err)
+	}
+
+	batch := index.NewBatch()
+	updated := 0
+	for _, hit := range results.Hits {
+		ttps := fieldStrings(hit.Fields["ttps"])
+		if len(ttps) == 0 {
+			continue
+		}
+
+		doc := SearchDocument{
+			Actor:         fieldString(hit.Fields["actor"]),
+			Campaign:      fieldString(hit.Fields["campaign"]),
+			TTPs:          ttps,
+			Tactics:       fieldStrings(hit.Fields["tactics"]),
+			Platforms:     fieldStrings(hit.Fields["platforms"]),
+			Confidence:    fieldFloat(hit.Fields["confidence"]),
+			FirstSeen:     fieldTime(hit.Fields["first_seen"]),
+			LastSeen:      fieldTime(hit.Fields["last_seen"]),
+			AllSourceText: fieldString(hit.Fields["all_source_text"]),
+			Type:          "genome",
+			CVEIDs:        fieldStrings(hit.Fields["cve_id"]),
+			MaxCVSSScore:  fieldFloat(hit.Fields["cvss_score"]),
+			KEVExploited:  fieldBool(hit.Fields["kev_exploited"]),
+			Detections:    fieldStrings(hit.Fields["detections"]),
+			GeoCountries:  fieldStrings(hit.Fields["geo_countries"]),
+			GeoASNs:       fieldStrings(hit.Fields["geo_asns"]),
+			TTPSequence:   strings.Join(ttps, " "),
+		}
+
+		if err := batch.Index(hit.ID, doc); err != nil {
+			return fmt.Errorf("failed to stage backfill for %s: %w", hit.ID, err)
+		}
+		updated++
+	}
+
+	if err := index.Batch(batch); err != nil {
+		return fmt.Errorf("failed to apply backfill batch: %w", err)
+	}
+
+	log.Printf("✅ Backfilled ttp_sequence field for %d genomes in %s", updated, indexPath)
+	return nil
+}
@@ -0,0 +1,173 @@
+package threatdnacore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultSearchCacheTTL bounds how long a cached search result stays valid
+// when RedisCache's caller doesn't specify one.
+const defaultSearchCacheTTL = 5 * time.Minute
+
+const (
+	redisGenomeKeyPrefix = "genome:"
+	redisActorSetPrefix  = "actor:"
+)
+
+// RedisCache is an optional hot-path layer in front of the GenomeBuilder's
+// primary Bleve store, so read-heavy multi-instance API deployments don't
+// all hit Bleve directly. GenomeBuilder writes through to it on every
+// genome update via PutGenome; read paths (e.g. the search API) check it
+// first and fall back to Bleve/Bolt on a miss.
+type RedisCache struct {
+	client *redis.Client
+	Search *SearchCache
+}
+
+// NewRedisCache connects to the Redis instance at url (e.g.
+// "redis://localhost:6379/0"). searchTTL of 0 falls back to
+// defaultSearchCacheTTL.
+func NewRedisCache(url string, searchTTL time.Duration) (*RedisCache, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redis URL %q: %w", url, err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", url, err)
+	}
+	if searchTTL <= 0 {
+		searchTTL = defaultSearchCacheTTL
+	}
+	return &RedisCache{
+		client: client,
+		Search: &SearchCache{client: client, ttl: searchTTL},
+	}, nil
+}
+
+// GetGenome returns the cached genome for id, or (nil, false) on a miss.
+func (c *RedisCache) GetGenome(id string) (*Genome, bool) {
+	data, err := c.client.Get(context.Background(), redisGenomeKeyPrefix+id).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	var genome Genome
+	if err := json.Unmarshal(data, &genome); err != nil {
+		return nil, false
+	}
+	return &genome, true
+}
+
+// PutGenome writes genome through to Redis and adds its ID to the
+// genome's actor secondary index set (actor:<name> -> {genomeIDs}), so
+// InvalidateActor and actor-filtered lookups never need to scan the whole
+// cache.
+func (c *RedisCache) PutGenome(genome *Genome) error {
+	data, err := json.Marshal(genome)
+	if err != nil {
+		return fmt.Errorf("failed to marshal genome %s for cache: %w", genome.ID, err)
+	}
+
+	ctx := context.Background()
+	pipe := c.client.TxPipeline()
+	pipe.Set(ctx, redisGenomeKeyPrefix+genome.ID, data, 0)
+	if genome.Actor != "" {
+		pipe.SAdd(ctx, redisActorSetPrefix+genome.Actor, genome.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to write genome %s through to redis: %w", genome.ID, err)
+	}
+	return nil
+}
+
+// InvalidateActor evicts every cached genome belonging to actor, using the
+// actor:<name> secondary index set instead of scanning the whole cache.
+func (c *RedisCache) InvalidateActor(actor string) error {
+	ctx := context.Background()
+	setKey := redisActorSetPrefix + actor
+
+	ids, err := c.client.SMembers(ctx, setKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list cached genomes for actor %s: %w", actor, err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = redisGenomeKeyPrefix + id
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.Del(ctx, keys...)
+	pipe.Del(ctx, setKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to invalidate cached genomes for actor %s: %w", actor, err)
+	}
+	return nil
+}
+
+// Close closes the underlying Redis connection.
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
+
+// SearchCache caches APISearchResult pages keyed by a hash of the query
+// text plus its filters, so repeated identical requests (the common case
+// under load) are served without a Bleve round-trip.
+type SearchCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// Get returns the cached results for query+filters, or (nil, false) on a
+// miss.
+func (s *SearchCache) Get(query string, filters map[string]string) ([]APISearchResult, bool) {
+	data, err := s.client.Get(context.Background(), searchCacheKey(query, filters)).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	var results []APISearchResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, false
+	}
+	return results, true
+}
+
+// Put caches results for query+filters under the cache's TTL.
+func (s *SearchCache) Put(query string, filters map[string]string, results []APISearchResult) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search results for cache: %w", err)
+	}
+	if err := s.client.Set(context.Background(), searchCacheKey(query, filters), data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to write search results to cache: %w", err)
+	}
+	return nil
+}
+
+// searchCacheKey hashes query+filters into a single deterministic Redis
+// key, sorting filter names first so key order in the caller's map never
+// changes the key.
+func searchCacheKey(query string, filters map[string]string) string {
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	h.Write([]byte(query))
+	for _, name := range names {
+		fmt.Fprintf(h, "|%s=%s", name, filters[name])
+	}
+	return "search:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,47 @@
+package threatdnacore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RecordCodec serializes a CTIRecord for publish and names the wire format
+// downstream consumers should dispatch on. cmd/producer resolves one from
+// KAFKA_VALUE_FORMAT (or a per-topic KAFKA_VALUE_FORMAT_<TOPIC> override)
+// and emits ContentType() as the published Kafka message's content-type
+// header, so a SIEM or TIP subscribed to the same topic can tell a STIX
+// bundle from a CEF line from plain JSON without inspecting the payload.
+type RecordCodec interface {
+	Encode(record CTIRecord) ([]byte, error)
+	ContentType() string
+}
+
+// NewRecordCodec resolves format to a RecordCodec. An empty format selects
+// JSONCodec, the producer's original wire shape.
+func NewRecordCodec(format string) (RecordCodec, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "json":
+		return JSONCodec{}, nil
+	case "stix", "stix2", "stix2.1":
+		return STIXCodec{}, nil
+	case "cef":
+		return CEFCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown value format %q (want json, stix2.1, or cef)", format)
+	}
+}
+
+// JSONCodec is the producer's original wire format: the CTIRecord shape
+// verbatim.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(record CTIRecord) ([]byte, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal record %s as JSON: %w", record.ID, err)
+	}
+	return data, nil
+}
+
+func (JSONCodec) ContentType() string { return "application/json" }
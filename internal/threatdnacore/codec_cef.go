@@ -0,0 +1,97 @@
+package threatdnacore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CEFCodec renders a CTIRecord as one ArcSight Common Event Format line,
+// the format most SIEMs (ArcSight, Splunk, QRadar) accept out of the box:
+//
+//	CEF:Version|Device Vendor|Device Product|Device Version|Signature ID|Name|Severity|Extension
+type CEFCodec struct{}
+
+// cefSeverity maps a DetectionHit.Severity string to CEF's 0-10 scale.
+// Records with no detections, or an unrecognized severity, default to 5
+// (medium) rather than silently becoming informational.
+func cefSeverity(record CTIRecord) int {
+	highest := -1
+	for _, hit := range record.Detections {
+		var s int
+		switch strings.ToLower(hit.Severity) {
+		case "critical":
+			s = 10
+		case "high":
+			s = 8
+		case "medium":
+			s = 5
+		case "low":
+			s = 2
+		default:
+			continue
+		}
+		if s > highest {
+			highest = s
+		}
+	}
+	if highest < 0 {
+		return 5
+	}
+	return highest
+}
+
+func (CEFCodec) Encode(record CTIRecord) ([]byte, error) {
+	name := record.Actor
+	if name == "" {
+		name = record.Campaign
+	}
+	if name == "" {
+		name = "Unclassified CTI Record"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CEF:0|ThreatDNA|cti-producer|1.0|%s|%s|%d|",
+		cefEscapeHeader(record.ID), cefEscapeHeader(name), cefSeverity(record))
+
+	ext := make([]string, 0, 6)
+	ext = append(ext, fmt.Sprintf("rt=%d", record.Date.UnixMilli()))
+	if record.Actor != "" {
+		ext = append(ext, "cs1Label=Actor", "cs1="+cefEscapeExtension(record.Actor))
+	}
+	if record.Campaign != "" {
+		ext = append(ext, "cs2Label=Campaign", "cs2="+cefEscapeExtension(record.Campaign))
+	}
+	if len(record.TTPs) > 0 {
+		techniques := make([]string, len(record.TTPs))
+		for i, ttp := range record.TTPs {
+			techniques[i] = ttp.TechniqueID
+		}
+		ext = append(ext, "cs3Label=Techniques", "cs3="+cefEscapeExtension(strings.Join(techniques, ",")))
+	}
+	ext = append(ext, fmt.Sprintf("cnt=%d", len(record.IOCs)))
+	ext = append(ext, "msg="+cefEscapeExtension(record.RawText))
+
+	b.WriteString(strings.Join(ext, " "))
+	return []byte(b.String()), nil
+}
+
+func (CEFCodec) ContentType() string { return "text/cef" }
+
+// cefEscapeHeader escapes CEF header fields (everything before Extension),
+// where '|' separates fields and '\' must itself be escaped.
+func cefEscapeHeader(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `|`, `\|`)
+	return s
+}
+
+// cefEscapeExtension escapes CEF extension values, where '=' separates a
+// key from its value and '\' must itself be escaped. Newlines are
+// flattened so one record stays one CEF line.
+func cefEscapeExtension(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `=`, `\=`)
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\r", " ")
+	return s
+}
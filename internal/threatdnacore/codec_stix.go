@@ -0,0 +1,159 @@
+package threatdnacore
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// STIXCodec renders a single CTIRecord as a STIX 2.1 bundle: the record
+// itself becomes an intrusion-set (or campaign, if Campaign is set) SDO,
+// one attack-pattern per TTP with a "uses" relationship, one indicator per
+// IOC with an "indicates" relationship, and one malware SDO per detection
+// hit with a "uses" relationship. This mirrors stix_genome.go's ExportSTIX
+// SDO/SRO shapes, scoped to a single ingested record rather than an
+// aggregated genome, since the producer publishes before genomes exist.
+type STIXCodec struct{}
+
+// stixRecordObject covers every SDO/SRO type STIXCodec emits. It mirrors
+// genomeSTIXObject plus the malware-specific fields this shape needs.
+type stixRecordObject struct {
+	Type               string              `json:"type"`
+	SpecVersion        string              `json:"spec_version"`
+	ID                 string              `json:"id"`
+	Created            string              `json:"created"`
+	Modified           string              `json:"modified"`
+	Name               string              `json:"name,omitempty"`
+	Pattern            string              `json:"pattern,omitempty"`
+	PatternType        string              `json:"pattern_type,omitempty"`
+	ValidFrom          string              `json:"valid_from,omitempty"`
+	IsFamily           *bool               `json:"is_family,omitempty"`
+	RelationshipType   string              `json:"relationship_type,omitempty"`
+	SourceRef          string              `json:"source_ref,omitempty"`
+	TargetRef          string              `json:"target_ref,omitempty"`
+	ExternalReferences []ExternalReference `json:"external_references,omitempty"`
+}
+
+type stixRecordBundle struct {
+	Type    string             `json:"type"`
+	ID      string             `json:"id"`
+	Objects []stixRecordObject `json:"objects"`
+}
+
+func (STIXCodec) Encode(record CTIRecord) ([]byte, error) {
+	created := stixTimestampFor(record.Date)
+
+	subjectType := "intrusion-set"
+	subjectName := record.Actor
+	if record.Campaign != "" {
+		subjectType = "campaign"
+		subjectName = record.Campaign
+	}
+	if subjectName == "" {
+		subjectName = record.ID
+	}
+	subjectID := generateSTIXID(subjectType, subjectName)
+
+	bundle := stixRecordBundle{
+		Type: "bundle",
+		ID:   generateSTIXID("bundle", record.ID),
+		Objects: []stixRecordObject{{
+			Type:        subjectType,
+			SpecVersion: "2.1",
+			ID:          subjectID,
+			Created:     created,
+			Modified:    created,
+			Name:        subjectName,
+		}},
+	}
+
+	attackPatternIDs := make(map[string]string)
+	for _, ttp := range record.TTPs {
+		attackPatternID, ok := attackPatternIDs[ttp.TechniqueID]
+		if !ok {
+			attackPatternID = generateSTIXID("attack-pattern", ttp.TechniqueID)
+			attackPatternIDs[ttp.TechniqueID] = attackPatternID
+			bundle.Objects = append(bundle.Objects, stixRecordObject{
+				Type:        "attack-pattern",
+				SpecVersion: "2.1",
+				ID:          attackPatternID,
+				Created:     created,
+				Modified:    created,
+				Name:        ttp.TechniqueID,
+				ExternalReferences: []ExternalReference{
+					{SourceName: "mitre-attack", ExternalID: ttp.TechniqueID},
+				},
+			})
+		}
+		bundle.Objects = append(bundle.Objects, stixRecordObject{
+			Type:             "relationship",
+			SpecVersion:      "2.1",
+			ID:               generateSTIXID("relationship", fmt.Sprintf("%s:uses:%s", subjectID, attackPatternID)),
+			Created:          created,
+			Modified:         created,
+			RelationshipType: "uses",
+			SourceRef:        subjectID,
+			TargetRef:        attackPatternID,
+		})
+	}
+
+	for _, ioc := range record.IOCs {
+		indicatorID := generateSTIXID("indicator", fmt.Sprintf("%s:%s", ioc.Type, ioc.Value))
+		bundle.Objects = append(bundle.Objects, stixRecordObject{
+			Type:        "indicator",
+			SpecVersion: "2.1",
+			ID:          indicatorID,
+			Created:     created,
+			Modified:    created,
+			Pattern:     stixPatternForIOC(ioc),
+			PatternType: "stix",
+			ValidFrom:   created,
+		})
+		bundle.Objects = append(bundle.Objects, stixRecordObject{
+			Type:             "relationship",
+			SpecVersion:      "2.1",
+			ID:               generateSTIXID("relationship", fmt.Sprintf("%s:indicates:%s", indicatorID, subjectID)),
+			Created:          created,
+			Modified:         created,
+			RelationshipType: "indicates",
+			SourceRef:        indicatorID,
+			TargetRef:        subjectID,
+		})
+	}
+
+	isFamily := false
+	malwareIDs := make(map[string]string)
+	for _, hit := range record.Detections {
+		malwareID, ok := malwareIDs[hit.TemplateID]
+		if !ok {
+			malwareID = generateSTIXID("malware", hit.TemplateID)
+			malwareIDs[hit.TemplateID] = malwareID
+			bundle.Objects = append(bundle.Objects, stixRecordObject{
+				Type:        "malware",
+				SpecVersion: "2.1",
+				ID:          malwareID,
+				Created:     created,
+				Modified:    created,
+				Name:        hit.TemplateID,
+				IsFamily:    &isFamily,
+			})
+		}
+		bundle.Objects = append(bundle.Objects, stixRecordObject{
+			Type:             "relationship",
+			SpecVersion:      "2.1",
+			ID:               generateSTIXID("relationship", fmt.Sprintf("%s:uses:%s", subjectID, malwareID)),
+			Created:          created,
+			Modified:         created,
+			RelationshipType: "uses",
+			SourceRef:        subjectID,
+			TargetRef:        malwareID,
+		})
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal STIX bundle for record %s: %w", record.ID, err)
+	}
+	return data, nil
+}
+
+func (STIXCodec) ContentType() string { return "application/stix+json;version=2.1" }
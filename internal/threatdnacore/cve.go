@@ -0,0 +1,234 @@
+package threatdnacore
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnrichedCVE is the metadata we attach to a bare CVE ID discovered in a
+// CTI record, joined from a locally cached NVD feed and the CISA KEV
+// catalog.
+type EnrichedCVE struct {
+	ID              string    `json:"id"`
+	CVSSScore       float64   `json:"cvss_score"`
+	CVSSVector      string    `json:"cvss_vector,omitempty"`
+	CWE             string    `json:"cwe,omitempty"`
+	Vendor          string    `json:"vendor,omitempty"`
+	Product         string    `json:"product,omitempty"`
+	AffectedVersions []string `json:"affected_versions,omitempty"`
+	Published       time.Time `json:"published,omitempty"`
+	Description     string    `json:"description,omitempty"`
+	References      []string  `json:"references,omitempty"`
+	Exploited       bool      `json:"exploited"`
+	RansomwareUse   bool      `json:"ransomware_use"`
+	DueDate         time.Time `json:"due_date,omitempty"`
+}
+
+// nvdFeedEntry mirrors the subset of NVD's JSON feed schema we care about.
+type nvdFeedEntry struct {
+	ID          string   `json:"id"`
+	CVSSScore   float64  `json:"cvss_score"`
+	CVSSVector  string   `json:"cvss_vector"`
+	CWE         string   `json:"cwe"`
+	Vendor      string   `json:"vendor"`
+	Product     string   `json:"product"`
+	Versions    []string `json:"versions"`
+	Published   time.Time `json:"published"`
+	Description string   `json:"description"`
+	References  []string `json:"references"`
+}
+
+// kevEntry mirrors the subset of the CISA Known Exploited Vulnerabilities
+// catalog we care about.
+type kevEntry struct {
+	CVEID              string `json:"cveID"`
+	KnownRansomwareUse string `json:"knownRansomwareCampaignUse"`
+	DueDate            string `json:"dueDate"` // "2006-01-02", as the catalog writes it
+}
+
+// CVEEnricher joins bare CVE IDs discovered during ingest against a locally
+// cached NVD feed and the CISA KEV catalog. It runs entirely offline against
+// files under feedDir and supports periodic refresh via StartBackgroundRefresh.
+type CVEEnricher struct {
+	feedDir string
+	kevPath string
+
+	mu  sync.RWMutex
+	nvd map[string]nvdFeedEntry
+	kev map[string]kevEntry
+}
+
+// NewCVEEnricher creates a CVEEnricher reading NVD feed files from feedDir
+// (e.g. "data/nvd/*.json") and the CISA KEV catalog from kevPath. Missing
+// files are tolerated so ingestion keeps working without enrichment data.
+func NewCVEEnricher(feedDir, kevPath string) (*CVEEnricher, error) {
+	e := &CVEEnricher{
+		feedDir: feedDir,
+		kevPath: kevPath,
+		nvd:     make(map[string]nvdFeedEntry),
+		kev:     make(map[string]kevEntry),
+	}
+	if err := e.Refresh(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Refresh reloads the NVD feed and KEV catalog from disk.
+func (e *CVEEnricher) Refresh() error {
+	nvd, err := loadNVDFeed(e.feedDir)
+	if err != nil {
+		return fmt.Errorf("failed to load NVD feed from %s: %w", e.feedDir, err)
+	}
+
+	kev, err := loadKEVCatalog(e.kevPath)
+	if err != nil {
+		return fmt.Errorf("failed to load CISA KEV catalog from %s: %w", e.kevPath, err)
+	}
+
+	e.mu.Lock()
+	e.nvd = nvd
+	e.kev = kev
+	e.mu.Unlock()
+
+	log.Printf("✅ CVE enricher loaded %d NVD entries, %d KEV entries", len(nvd), len(kev))
+	return nil
+}
+
+// StartBackgroundRefresh periodically reloads the feed files so long-running
+// processes (the producer, the builder) pick up newly downloaded data
+// without restarting. Stop the refresh by closing stop.
+func (e *CVEEnricher) StartBackgroundRefresh(stop <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := e.Refresh(); err != nil {
+					log.Printf("⚠️  CVE enricher refresh failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Enrich looks up a single CVE ID (e.g. "CVE-2024-12345") against the loaded
+// NVD/KEV data. The second return value is false if nothing is known about
+// the CVE, in which case callers still get an EnrichedCVE carrying just ID.
+func (e *CVEEnricher) Enrich(cveID string) (EnrichedCVE, bool) {
+	cveID = strings.ToUpper(strings.TrimSpace(cveID))
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	result := EnrichedCVE{ID: cveID}
+	nvd, haveNVD := e.nvd[cveID]
+	if haveNVD {
+		result.CVSSScore = nvd.CVSSScore
+		result.CVSSVector = nvd.CVSSVector
+		result.CWE = nvd.CWE
+		result.Vendor = nvd.Vendor
+		result.Product = nvd.Product
+		result.AffectedVersions = nvd.Versions
+		result.Published = nvd.Published
+		result.Description = nvd.Description
+		result.References = nvd.References
+	}
+
+	if kev, ok := e.kev[cveID]; ok {
+		result.Exploited = true
+		result.RansomwareUse = strings.EqualFold(kev.KnownRansomwareUse, "Known")
+		if due, err := time.Parse("2006-01-02", kev.DueDate); err == nil {
+			result.DueDate = due
+		}
+	}
+
+	return result, haveNVD || result.Exploited
+}
+
+// EnrichAll enriches every CVE ID found in a record's IOCs (type "cve").
+func (e *CVEEnricher) EnrichAll(iocs []IOC) []EnrichedCVE {
+	var enriched []EnrichedCVE
+	seen := make(map[string]bool)
+	for _, ioc := range iocs {
+		if ioc.Type != "cve" || seen[ioc.Value] {
+			continue
+		}
+		seen[ioc.Value] = true
+		cve, _ := e.Enrich(ioc.Value)
+		enriched = append(enriched, cve)
+	}
+	return enriched
+}
+
+func loadNVDFeed(dir string) (map[string]nvdFeedEntry, error) {
+	entries := make(map[string]nvdFeedEntry)
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return entries, nil
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") || f.Name() == "kev.json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			log.Printf("⚠️  Skipping NVD feed file %s: %v", f.Name(), err)
+			continue
+		}
+
+		var page []nvdFeedEntry
+		if err := json.Unmarshal(data, &page); err != nil {
+			log.Printf("⚠️  Skipping NVD feed file %s: %v", f.Name(), err)
+			continue
+		}
+
+		for _, record := range page {
+			entries[strings.ToUpper(record.ID)] = record
+		}
+	}
+
+	return entries, nil
+}
+
+func loadKEVCatalog(path string) (map[string]kevEntry, error) {
+	entries := make(map[string]kevEntry)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return entries, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog struct {
+		Vulnerabilities []kevEntry `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, err
+	}
+
+	for _, v := range catalog.Vulnerabilities {
+		entries[strings.ToUpper(v.CVEID)] = v
+	}
+
+	return entries, nil
+}
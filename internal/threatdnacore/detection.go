@@ -0,0 +1,294 @@
+package threatdnacore
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Matcher is a single condition within a DetectionTemplate. Exactly one of
+// its fields is expected to be set; Not inverts the outcome before the
+// template's boolean condition combines it with the others.
+type Matcher struct {
+	TTP          string   `yaml:"ttp,omitempty"`
+	IOCRegex     string   `yaml:"ioc_regex,omitempty"`
+	Actor        []string `yaml:"actor,omitempty"`
+	CVE          string   `yaml:"cve,omitempty"`
+	KEVExploited bool     `yaml:"kev_exploited,omitempty"`
+	Keyword      string   `yaml:"keyword,omitempty"`
+	Not          bool     `yaml:"not,omitempty"`
+
+	iocRegex *regexp.Regexp
+}
+
+func (m *Matcher) compile() error {
+	if m.IOCRegex == "" {
+		return nil
+	}
+	compiled, err := regexp.Compile("(?i)" + m.IOCRegex)
+	if err != nil {
+		return fmt.Errorf("invalid ioc_regex %q: %w", m.IOCRegex, err)
+	}
+	m.iocRegex = compiled
+	return nil
+}
+
+func (m *Matcher) evaluate(record *CTIRecord) bool {
+	var result bool
+	switch {
+	case m.TTP != "":
+		result = recordHasTTP(record, m.TTP)
+	case m.CVE != "":
+		result = recordHasCVE(record, m.CVE)
+	case m.KEVExploited:
+		result = recordHasKEVExploitedCVE(record)
+	case len(m.Actor) > 0:
+		result = recordHasActor(record, m.Actor)
+	case m.iocRegex != nil:
+		result = recordMatchesIOCRegex(record, m.iocRegex)
+	case m.Keyword != "":
+		result = strings.Contains(strings.ToLower(record.RawText), strings.ToLower(m.Keyword))
+	}
+	if m.Not {
+		return !result
+	}
+	return result
+}
+
+func recordHasTTP(record *CTIRecord, techniqueID string) bool {
+	for _, ttp := range record.TTPs {
+		if ttp.TechniqueID == techniqueID {
+			return true
+		}
+	}
+	return false
+}
+
+func recordHasCVE(record *CTIRecord, cveID string) bool {
+	for _, cve := range record.CVEs {
+		if strings.EqualFold(cve.ID, cveID) {
+			return true
+		}
+	}
+	return false
+}
+
+func recordHasKEVExploitedCVE(record *CTIRecord) bool {
+	for _, cve := range record.CVEs {
+		if cve.Exploited {
+			return true
+		}
+	}
+	return false
+}
+
+func recordHasActor(record *CTIRecord, candidates []string) bool {
+	for _, candidate := range candidates {
+		if strings.EqualFold(record.Actor, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func recordMatchesIOCRegex(record *CTIRecord, pattern *regexp.Regexp) bool {
+	for _, ioc := range record.IOCs {
+		if pattern.MatchString(ioc.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectionTemplate expresses a composite condition over an ingested
+// CTIRecord: a list of matchers (TTP presence, IOC regex, actor names, CVE
+// presence, text keywords) glued by and/or boolean logic.
+type DetectionTemplate struct {
+	ID         string    `yaml:"id"`
+	Severity   string    `yaml:"severity"`
+	Tags       []string  `yaml:"tags"`
+	References []string  `yaml:"references"`
+	Condition  string    `yaml:"condition"` // "and" (default) or "or"
+	Matchers   []Matcher `yaml:"matchers"`
+}
+
+func (t *DetectionTemplate) compile() error {
+	for i := range t.Matchers {
+		if err := t.Matchers[i].compile(); err != nil {
+			return fmt.Errorf("template %s: %w", t.ID, err)
+		}
+	}
+	return nil
+}
+
+// Evaluate reports whether the template's matchers are satisfied by record.
+func (t *DetectionTemplate) Evaluate(record *CTIRecord) bool {
+	if len(t.Matchers) == 0 {
+		return false
+	}
+
+	if strings.EqualFold(t.Condition, "or") {
+		for _, m := range t.Matchers {
+			if m.evaluate(record) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, m := range t.Matchers {
+		if !m.evaluate(record) {
+			return false
+		}
+	}
+	return true
+}
+
+// templateFile is the on-disk YAML shape: one file can define many templates.
+type templateFile struct {
+	Templates []DetectionTemplate `yaml:"templates"`
+}
+
+// TemplateEngine loads a directory of DetectionTemplates and evaluates every
+// ingested record against them, turning the extractor from a passive tagger
+// into a rule-driven hunting platform.
+type TemplateEngine struct {
+	mu        sync.RWMutex
+	templates []*DetectionTemplate
+}
+
+// NewTemplateEngine creates an empty engine; call LoadFromDir to populate it.
+func NewTemplateEngine() *TemplateEngine {
+	return &TemplateEngine{}
+}
+
+// LoadTemplateEngine loads every *.yaml/*.yml template file under dir. If
+// dir does not exist, the engine falls back to a small built-in standard
+// library of templates so detections keep working without a configured
+// template directory.
+func LoadTemplateEngine(dir string) (*TemplateEngine, error) {
+	te := NewTemplateEngine()
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		log.Printf("⚠️  Templates directory %s not found, using built-in standard library", dir)
+		te.templates = standardTemplateLibrary()
+		return te, nil
+	}
+	if err := te.LoadFromDir(dir); err != nil {
+		return nil, err
+	}
+	return te, nil
+}
+
+// LoadFromDir (re)loads every rule file under dir, replacing the active
+// templates.
+func (te *TemplateEngine) LoadFromDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read templates directory %s: %w", dir, err)
+	}
+
+	var templates []*DetectionTemplate
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			log.Printf("⚠️  Skipping template file %s: %v", name, err)
+			continue
+		}
+
+		var tf templateFile
+		if err := yaml.Unmarshal(data, &tf); err != nil {
+			log.Printf("⚠️  Skipping template file %s: %v", name, err)
+			continue
+		}
+
+		for i := range tf.Templates {
+			tmpl := &tf.Templates[i]
+			if err := tmpl.compile(); err != nil {
+				log.Printf("⚠️  Skipping template %s in %s: %v", tmpl.ID, name, err)
+				continue
+			}
+			templates = append(templates, tmpl)
+		}
+	}
+
+	te.mu.Lock()
+	te.templates = templates
+	te.mu.Unlock()
+
+	log.Printf("✅ Loaded %d detection templates from %s", len(templates), dir)
+	return nil
+}
+
+// Evaluate runs every loaded template against record and returns the hits.
+// It does not mutate record; callers assign the result to record.Detections.
+func (te *TemplateEngine) Evaluate(record *CTIRecord) []DetectionHit {
+	te.mu.RLock()
+	defer te.mu.RUnlock()
+
+	var hits []DetectionHit
+	for _, tmpl := range te.templates {
+		if tmpl.Evaluate(record) {
+			hits = append(hits, DetectionHit{
+				TemplateID: tmpl.ID,
+				Severity:   tmpl.Severity,
+				Tags:       tmpl.Tags,
+			})
+		}
+	}
+	return hits
+}
+
+// standardTemplateLibrary is the small built-in set of detections shipped
+// so the engine is useful out of the box.
+func standardTemplateLibrary() []*DetectionTemplate {
+	templates := []*DetectionTemplate{
+		{
+			ID:        "ransomware-double-extortion",
+			Severity:  "critical",
+			Tags:      []string{"ransomware", "extortion"},
+			Condition: "and",
+			Matchers: []Matcher{
+				{TTP: "T1486"},
+				{TTP: "T1567"},
+				{Actor: []string{"LockBit", "BlackCat", "ALPHV", "Conti", "Cl0p"}},
+			},
+		},
+		{
+			ID:        "credential-dumping-with-c2",
+			Severity:  "high",
+			Tags:      []string{"credential-access", "command-and-control"},
+			Condition: "and",
+			Matchers: []Matcher{
+				{TTP: "T1003"},
+				{TTP: "T1071"},
+			},
+		},
+		{
+			ID:        "actively-exploited-cve",
+			Severity:  "critical",
+			Tags:      []string{"known-exploited"},
+			Condition: "and",
+			Matchers: []Matcher{
+				{KEVExploited: true},
+			},
+		},
+	}
+	for _, t := range templates {
+		_ = t.compile()
+	}
+	return templates
+}
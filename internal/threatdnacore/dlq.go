@@ -0,0 +1,86 @@
+package threatdnacore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"threatdna/internal/kafkaio"
+)
+
+// DefaultDLQTopic is where DLQPublisher sends a record once its retry
+// budget is exhausted, unless the caller names a different one.
+const DefaultDLQTopic = "cti-records-dlq"
+
+// DLQPublisher wraps a kafkaio.Producer with a shared retry/dead-letter
+// policy, so the batch producer (cmd/producer's publishInitialData) and
+// any future streaming producer (e.g. Watcher's publish callback) fail
+// the same way instead of each reimplementing its own retry loop.
+type DLQPublisher struct {
+	Producer *kafkaio.Producer
+	DLQTopic string
+	Policy   kafkaio.RetryPolicy
+}
+
+// NewDLQPublisher returns a DLQPublisher over producer using
+// DefaultDLQTopic and kafkaio.DefaultRetryPolicy.
+func NewDLQPublisher(producer *kafkaio.Producer) *DLQPublisher {
+	return &DLQPublisher{
+		Producer: producer,
+		DLQTopic: DefaultDLQTopic,
+		Policy:   kafkaio.DefaultRetryPolicy(),
+	}
+}
+
+// PublishWithDLQ publishes rec, retrying on failure per p.Policy's
+// exponential backoff with jitter. If every attempt fails, rec is
+// republished to p.DLQTopic with headers describing the original topic,
+// the last error, the attempt count, and a trace id correlating the DLQ
+// entry back to this call's log lines — so the record is recorded rather
+// than silently dropped. Only a failure to reach the DLQ topic itself is
+// returned as an error.
+func (p *DLQPublisher) PublishWithDLQ(ctx context.Context, rec *kafkaio.Record) error {
+	trace := kafkaio.TraceID()
+	maxAttempts := p.Policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		lastErr = p.Producer.Produce(ctx, rec)
+		if lastErr == nil {
+			return nil
+		}
+
+		log.Printf("❌ [trace=%s] publish attempt %d/%d to %s failed: %v", trace, attempt, maxAttempts, rec.Topic, lastErr)
+		if attempt == maxAttempts {
+			break
+		}
+		select {
+		case <-time.After(p.Policy.Delay(attempt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	dlqRec := &kafkaio.Record{
+		Topic: p.DLQTopic,
+		Key:   rec.Key,
+		Value: rec.Value,
+		Headers: map[string]string{
+			"x-original-topic": rec.Topic,
+			"x-error":          lastErr.Error(),
+			"x-attempts":       fmt.Sprintf("%d", maxAttempts),
+			"x-dlq-trace-id":   trace,
+		},
+		Time: time.Now(),
+	}
+	if err := p.Producer.Produce(ctx, dlqRec); err != nil {
+		return fmt.Errorf("failed to publish record %s to DLQ topic %s after %d failed attempts on %s: %w", string(rec.Key), p.DLQTopic, maxAttempts, rec.Topic, err)
+	}
+
+	log.Printf("⚠️  [trace=%s] record %s dead-lettered to %s after %d failed attempts on %s", trace, string(rec.Key), p.DLQTopic, maxAttempts, rec.Topic)
+	return nil
+}
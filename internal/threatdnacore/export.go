@@ -0,0 +1,356 @@
+package threatdnacore
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExportFormat selects the artifact shape SubmitExport writes.
+type ExportFormat string
+
+const (
+	ExportFormatCSV   ExportFormat = "csv"
+	ExportFormatJSONL ExportFormat = "jsonl"
+	ExportFormatSTIX2 ExportFormat = "stix2"
+)
+
+// ExportFilter narrows which genomes an export job includes. A zero value
+// field is not applied, so an empty ExportFilter exports everything.
+type ExportFilter struct {
+	Actor         string
+	Campaign      string
+	TTPs          []string // genome must contain every technique ID listed
+	Tactic        string
+	Platform      string
+	CVE           string
+	DateFrom      time.Time
+	DateTo        time.Time
+	MinConfidence float64
+}
+
+// Matches reports whether genome satisfies every non-zero field of f.
+func (f ExportFilter) Matches(genome *Genome) bool {
+	if f.Actor != "" && !strings.Contains(strings.ToLower(genome.Actor), strings.ToLower(f.Actor)) {
+		return false
+	}
+	if f.Campaign != "" && !strings.Contains(strings.ToLower(genome.Campaign), strings.ToLower(f.Campaign)) {
+		return false
+	}
+	for _, want := range f.TTPs {
+		if !containsFold(genome.TTPs, want) {
+			return false
+		}
+	}
+	if f.Tactic != "" && !containsFold(genome.Tactics, f.Tactic) {
+		return false
+	}
+	if f.Platform != "" && !containsSubstringFold(genome.Platforms, f.Platform) {
+		return false
+	}
+	if f.CVE != "" && !containsFold(genome.CVEs, f.CVE) {
+		return false
+	}
+	if !f.DateFrom.IsZero() && genome.LastSeen.Before(f.DateFrom) {
+		return false
+	}
+	if !f.DateTo.IsZero() && genome.FirstSeen.After(f.DateTo) {
+		return false
+	}
+	if f.MinConfidence > 0 && genome.Confidence < f.MinConfidence {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSubstringFold(values []string, target string) bool {
+	target = strings.ToLower(target)
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportStatus is the lifecycle state of an ExportJob.
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// ExportJob tracks one SubmitExport run: its progress while it streams
+// genomes out of the store, and the downloadable artifact once it's done.
+type ExportJob struct {
+	ExecutionID  string       `json:"execution_id"`
+	Status       ExportStatus `json:"status"`
+	Progress     int          `json:"progress"`
+	Format       ExportFormat `json:"format"`
+	RecordCount  int          `json:"record_count,omitempty"`
+	ArtifactPath string       `json:"artifact_path,omitempty"`
+	SHA256       string       `json:"sha256,omitempty"`
+	Error        string       `json:"error,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	CompletedAt  time.Time    `json:"completed_at,omitempty"`
+}
+
+// Exporter runs genome export jobs asynchronously against a GenomeBuilder's
+// store, writing artifacts under outputDir.
+type Exporter struct {
+	builder   *GenomeBuilder
+	outputDir string
+
+	mu   sync.RWMutex
+	jobs map[string]*ExportJob
+}
+
+// NewExporter creates an Exporter writing artifacts under outputDir,
+// creating it if it doesn't already exist.
+func NewExporter(builder *GenomeBuilder, outputDir string) *Exporter {
+	return &Exporter{
+		builder:   builder,
+		outputDir: outputDir,
+		jobs:      make(map[string]*ExportJob),
+	}
+}
+
+// SubmitExport starts an export job in the background and returns its ID
+// immediately; poll GetJob for status and the eventual artifact.
+func (e *Exporter) SubmitExport(filter ExportFilter, format ExportFormat) string {
+	job := &ExportJob{
+		ExecutionID: generateExportID(),
+		Status:      ExportStatusPending,
+		Format:      format,
+		CreatedAt:   time.Now(),
+	}
+
+	e.mu.Lock()
+	e.jobs[job.ExecutionID] = job
+	e.mu.Unlock()
+
+	go e.run(job, filter, format)
+	return job.ExecutionID
+}
+
+// GetJob returns the job with the given ID and whether it exists.
+func (e *Exporter) GetJob(jobID string) (*ExportJob, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	job, ok := e.jobs[jobID]
+	return job, ok
+}
+
+func (e *Exporter) run(job *ExportJob, filter ExportFilter, format ExportFormat) {
+	e.update(job, func(j *ExportJob) { j.Status = ExportStatusRunning; j.Progress = 10 })
+
+	genomes, err := e.builder.ListGenomes("", "", 0)
+	if err != nil {
+		e.fail(job, fmt.Errorf("failed to list genomes: %w", err))
+		return
+	}
+
+	matched := make([]*Genome, 0, len(genomes))
+	for _, genome := range genomes {
+		if filter.Matches(genome) {
+			matched = append(matched, genome)
+		}
+	}
+	e.update(job, func(j *ExportJob) { j.Progress = 50 })
+
+	if err := os.MkdirAll(e.outputDir, 0755); err != nil {
+		e.fail(job, fmt.Errorf("failed to create export directory %s: %w", e.outputDir, err))
+		return
+	}
+
+	path := filepath.Join(e.outputDir, fmt.Sprintf("%s.%s", job.ExecutionID, exportFileExtension(format)))
+
+	var writeErr error
+	switch format {
+	case ExportFormatCSV:
+		writeErr = writeGenomesCSV(path, matched)
+	case ExportFormatJSONL:
+		writeErr = writeGenomesJSONL(path, matched)
+	case ExportFormatSTIX2:
+		writeErr = writeGenomesSTIX2(path, e.builder, matched)
+	default:
+		writeErr = fmt.Errorf("unsupported export format %q", format)
+	}
+	if writeErr != nil {
+		e.fail(job, writeErr)
+		return
+	}
+
+	digest, err := sha256File(path)
+	if err != nil {
+		e.fail(job, fmt.Errorf("failed to digest export artifact %s: %w", path, err))
+		return
+	}
+
+	e.update(job, func(j *ExportJob) {
+		j.Status = ExportStatusCompleted
+		j.Progress = 100
+		j.RecordCount = len(matched)
+		j.ArtifactPath = path
+		j.SHA256 = digest
+		j.CompletedAt = time.Now()
+	})
+}
+
+func (e *Exporter) update(job *ExportJob, fn func(*ExportJob)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	fn(job)
+}
+
+func (e *Exporter) fail(job *ExportJob, err error) {
+	e.update(job, func(j *ExportJob) {
+		j.Status = ExportStatusFailed
+		j.Error = err.Error()
+		j.CompletedAt = time.Now()
+	})
+}
+
+func exportFileExtension(format ExportFormat) string {
+	switch format {
+	case ExportFormatJSONL:
+		return "jsonl"
+	case ExportFormatSTIX2:
+		return "json"
+	default:
+		return "csv"
+	}
+}
+
+// generateExportID returns a time-ordered job ID so exports sort naturally
+// by creation order on disk.
+func generateExportID() string {
+	return fmt.Sprintf("export_%d", time.Now().UnixNano())
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// writeGenomesCSV flattens TTPs/tactics/platforms/CVEs into semicolon-joined
+// columns, since CSV has no native concept of a repeated field.
+func writeGenomesCSV(path string, genomes []*Genome) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	header := []string{"id", "actor", "campaign", "ttps", "tactics", "platforms", "cves", "confidence", "first_seen", "last_seen", "source_count", "ioc_count"}
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, genome := range genomes {
+		row := []string{
+			genome.ID,
+			genome.Actor,
+			genome.Campaign,
+			strings.Join(genome.TTPs, ";"),
+			strings.Join(RemoveDuplicates(genome.Tactics), ";"),
+			strings.Join(genome.Platforms, ";"),
+			strings.Join(genome.CVEs, ";"),
+			fmt.Sprintf("%.2f", genome.Confidence),
+			genome.FirstSeen.Format(time.RFC3339),
+			genome.LastSeen.Format(time.RFC3339),
+			fmt.Sprintf("%d", genome.SourceCount),
+			fmt.Sprintf("%d", genome.IOCCount),
+		}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row for genome %s: %w", genome.ID, err)
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// writeGenomesJSONL writes one Genome per line, for streaming consumption
+// by downstream tools without loading the whole export into memory.
+func writeGenomesJSONL(path string, genomes []*Genome) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, genome := range genomes {
+		if err := enc.Encode(genome); err != nil {
+			return fmt.Errorf("failed to write JSONL row for genome %s: %w", genome.ID, err)
+		}
+	}
+	return nil
+}
+
+// writeGenomesSTIX2 delegates to GenomeBuilder.ExportSTIX so the export job
+// path and ExportSTIX (which also emits indicator/vulnerability SDOs for a
+// genome's IOCs/CVEs) share one STIX bundle builder instead of drifting
+// apart.
+func writeGenomesSTIX2(path string, builder *GenomeBuilder, genomes []*Genome) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := builder.ExportSTIX(f, genomes); err != nil {
+		return fmt.Errorf("failed to write STIX bundle: %w", err)
+	}
+	return nil
+}
+
+// generateSTIXID deterministically derives a "{type}--{uuid}"-shaped STIX
+// identifier from objType and key, so re-exporting the same genome never
+// produces duplicate objects with different IDs.
+func generateSTIXID(objType, key string) string {
+	hash := sha256.Sum256([]byte(objType + ":" + key))
+	hex := fmt.Sprintf("%x", hash)
+	return fmt.Sprintf("%s--%s-%s-%s-%s-%s", objType, hex[0:8], hex[8:12], hex[12:16], hex[16:20], hex[20:32])
+}
+
+// stixTimestampFor renders t as a STIX 2.1 millisecond-precision UTC
+// timestamp, falling back to now for a zero value.
+func stixTimestampFor(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
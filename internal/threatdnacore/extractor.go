@@ -3,114 +3,195 @@ package threatdnacore
 import (
 	"fmt"
 	"log"
-	"regexp"
+	"math"
 	"strings"
 )
 
 // TechniqueExtractor handles rule-based technique extraction
 type TechniqueExtractor struct {
-	techniques  map[string]AttackTechnique
-	patterns    map[string]*regexp.Regexp
-	iocPatterns map[string]*regexp.Regexp
+	techniques map[string]AttackTechnique
+	automaton  *ahoCorasick
+	rules      *RuleSet
+	normalizer *IOCNormalizer
 }
 
-// NewTechniqueExtractor creates a new technique extractor
-func NewTechniqueExtractor(attackData map[string]AttackTechnique) *TechniqueExtractor {
+// NewTechniqueExtractor creates a new technique extractor. IOC patterns are
+// sourced from rules (ioc_ip/ioc_domain/ioc_hash/ioc_url/ioc_email
+// categories) so new IOC formats can be added without recompiling. Every
+// technique's name, ID, and long keywords are inserted as lowercased
+// patterns into a single Aho-Corasick automaton rather than compiled as
+// independent regexes, so ExtractTTPs scans a document in one linear pass
+// instead of ~600.
+func NewTechniqueExtractor(attackData map[string]AttackTechnique, rules *RuleSet) *TechniqueExtractor {
 	log.Println("🔧 Initializing technique patterns...")
-	
+
 	extractor := &TechniqueExtractor{
-		techniques:  attackData,
-		patterns:    make(map[string]*regexp.Regexp),
-		iocPatterns: make(map[string]*regexp.Regexp),
+		techniques: attackData,
+		automaton:  newAhoCorasick(),
+		rules:      rules,
+		normalizer: NewIOCNormalizer(),
 	}
 
-	// Build optimized regex patterns
 	patternCount := 0
 	for id, technique := range attackData {
-		patterns := []string{
-			regexp.QuoteMeta(technique.Name),
-			fmt.Sprintf(`\b%s\b`, regexp.QuoteMeta(id)),
-		}
-		
+		name := strings.ToLower(technique.Name)
+		extractor.automaton.insert(name, acPayload{techniqueID: id, kind: acPatternName, length: len(name)})
+		patternCount++
+
+		lowerID := strings.ToLower(id)
+		extractor.automaton.insert(lowerID, acPayload{techniqueID: id, kind: acPatternID, length: len(lowerID)})
+		patternCount++
+
 		// Add only high-value keywords
 		for _, keyword := range technique.Keywords {
-			if len(keyword) > 4 { // Only longer keywords
-				patterns = append(patterns, fmt.Sprintf(`\b%s\b`, regexp.QuoteMeta(keyword)))
-			}
-		}
-
-		if len(patterns) > 0 {
-			pattern := strings.Join(patterns, "|")
-			if compiled, err := regexp.Compile("(?i)" + pattern); err == nil {
-				extractor.patterns[id] = compiled
-				patternCount++
+			if len(keyword) <= 4 {
+				continue
 			}
+			lowerKeyword := strings.ToLower(keyword)
+			extractor.automaton.insert(lowerKeyword, acPayload{techniqueID: id, kind: acPatternKeyword, length: len(lowerKeyword)})
+			patternCount++
 		}
 	}
-
-	// Optimized IOC patterns
-	extractor.iocPatterns["ip"] = regexp.MustCompile(`\b(?:[0-9]{1,3}\.){3}[0-9]{1,3}\b`)
-	extractor.iocPatterns["domain"] = regexp.MustCompile(`\b[a-zA-Z0-9]([a-zA-Z0-9\-]{0,30}[a-zA-Z0-9])?(\.[a-zA-Z]{2,10})+\b`)
-	extractor.iocPatterns["hash"] = regexp.MustCompile(`\b[a-fA-F0-9]{32,64}\b`)
+	extractor.automaton.build()
 
 	log.Printf("✅ Created %d technique patterns", patternCount)
 	return extractor
 }
 
-// ExtractTTPs with performance optimization
+// ExtractTTPs scans text for every technique name/ID/keyword in one
+// automaton pass, capping the tracked match count per technique at 3 (the
+// same cap FindAllStringIndex(text, 3) applied) for confidence scoring.
+// ID/keyword hits are rejected unless they land on a word boundary,
+// matching the \b...\b semantics the old per-technique regexes enforced;
+// technique-name hits were never boundary-restricted, so that stays
+// unfiltered here too.
 func (e *TechniqueExtractor) ExtractTTPs(text string) []TTP {
+	const maxTrackedMatches = 3
+
+	type techniqueHit struct {
+		count      int
+		firstStart int
+		firstEnd   int
+	}
+
+	lower := strings.ToLower(text)
+	hits := make(map[string]*techniqueHit)
+
+	for _, m := range e.automaton.search(lower) {
+		start := m.end - m.payload.length
+		if m.payload.kind != acPatternName && !isWordBoundary(lower, start, m.end) {
+			continue
+		}
+
+		hit, ok := hits[m.payload.techniqueID]
+		if !ok {
+			hit = &techniqueHit{firstStart: start, firstEnd: m.end}
+			hits[m.payload.techniqueID] = hit
+		}
+		if hit.count < maxTrackedMatches {
+			hit.count++
+		}
+	}
+
 	var ttps []TTP
-	seen := make(map[string]bool)
+	for techniqueID, hit := range hits {
+		confidence := calculateConfidence(hit.count, text, techniqueID)
+		context := extractContext(text, hit.firstStart, hit.firstEnd, 40)
 
-	// Limit text processing for performance
-	if len(text) > 50000 {
-		text = text[:50000] + "..."
-	}
-
-	for techniqueID, pattern := range e.patterns {
-		matches := pattern.FindAllStringIndex(text, 3) // Limit to 3 matches per technique
-		if len(matches) > 0 {
-			if !seen[techniqueID] {
-				confidence := calculateConfidence(len(matches), text, techniqueID)
-				context := extractContext(text, matches[0][0], matches[0][1], 40)
-				
-				tactic := ""
-				if technique, exists := e.techniques[techniqueID]; exists && len(technique.Tactics) > 0 {
-					tactic = technique.Tactics[0]
-				}
-				
-				ttps = append(ttps, TTP{
-					TechniqueID: techniqueID,
-					Confidence:  confidence,
-					Context:     context,
-					Tactic:      tactic,
-				})
-				
-				seen[techniqueID] = true
-			}
+		tactic := ""
+		if technique, exists := e.techniques[techniqueID]; exists && len(technique.Tactics) > 0 {
+			tactic = technique.Tactics[0]
 		}
+
+		ttps = append(ttps, TTP{
+			TechniqueID: techniqueID,
+			Confidence:  confidence,
+			Context:     context,
+			Tactic:      tactic,
+		})
 	}
 
 	return ttps
 }
 
-// ExtractIOCs with limits
+// matchTerms returns the lowercased name/ID/keyword terms techniqueID
+// contributes to the automaton, i.e. the same terms ExtractTTPs matches a
+// document against for this technique. Callers that need to check whether
+// a technique occurs in some other span of text (e.g. linkCVEsToTTPs
+// scanning a paragraph) should match against these rather than
+// re-deriving their own heuristic.
+func (e *TechniqueExtractor) matchTerms(techniqueID string) []string {
+	technique, ok := e.techniques[techniqueID]
+	if !ok {
+		return []string{strings.ToLower(techniqueID)}
+	}
+
+	terms := []string{strings.ToLower(technique.Name), strings.ToLower(techniqueID)}
+	for _, keyword := range technique.Keywords {
+		if len(keyword) > 4 {
+			terms = append(terms, strings.ToLower(keyword))
+		}
+	}
+	return terms
+}
+
+// iocRuleCategories maps the IOC type string used throughout the codebase
+// to the rule category that supplies its patterns.
+var iocRuleCategories = map[string]RuleCategory{
+	"ip":     CategoryIOCIP,
+	"domain": CategoryIOCDomain,
+	"hash":   CategoryIOCHash,
+	"url":    CategoryIOCURL,
+	"email":  CategoryIOCEmail,
+	"cve":    CategoryCVE,
+	"secret": CategoryIOCSecret,
+}
+
+// ExtractIOCs refangs the text (hxxp -> http, [.] -> ., zero-width chars
+// stripped) before matching, then validates and canonicalizes every match
+// via the normalizer so deduping works on dedupe-friendly values, not raw
+// regex hits.
+//
+// secret-type matches are treated differently from the rest: a rule with
+// MinEntropy set (the generic-high-entropy rule) is only kept if the match's
+// Shannon entropy clears that bar, and the Context records the matched
+// rule's ID rather than surrounding prose, since the rule name (e.g.
+// "aws-access-key") is more useful to an analyst than the text around a
+// credential.
 func (e *TechniqueExtractor) ExtractIOCs(text string) []IOC {
 	var iocs []IOC
 	seen := make(map[string]bool)
 
-	for iocType, pattern := range e.iocPatterns {
-		matches := pattern.FindAllString(text, 20) // Limit IOCs per type
-		for _, match := range matches {
-			key := fmt.Sprintf("%s:%s", iocType, match)
-			if !seen[key] && isValidIOC(iocType, match) {
-				context := extractIOCContext(text, match, 25)
-				iocs = append(iocs, IOC{
-					Type:    iocType,
-					Value:   match,
-					Context: context,
-				})
-				seen[key] = true
+	refanged := e.normalizer.Refang(text)
+
+	for iocType, category := range iocRuleCategories {
+		for _, rule := range e.rules.Rules(category) {
+			for _, pattern := range rule.compiled {
+				matches := pattern.FindAllString(refanged, 20) // Limit IOCs per type
+				for _, match := range matches {
+					if rule.isBlacklisted(match) || !e.normalizer.Validate(iocType, match) {
+						continue
+					}
+					if rule.MinEntropy > 0 && (len(match) < 20 || shannonEntropy(match) < rule.MinEntropy) {
+						continue
+					}
+					canonical := e.normalizer.Canonicalize(iocType, match)
+					key := fmt.Sprintf("%s:%s", iocType, canonical)
+					if seen[key] {
+						continue
+					}
+					context := extractIOCContext(refanged, match, 25)
+					if category == CategoryIOCSecret {
+						context = rule.ID
+					}
+					iocs = append(iocs, IOC{
+						Type:    iocType,
+						Value:   canonical,
+						Raw:     match,
+						Context: context,
+					})
+					seen[key] = true
+				}
 			}
 		}
 	}
@@ -118,6 +199,29 @@ func (e *TechniqueExtractor) ExtractIOCs(text string) []IOC {
 	return iocs
 }
 
+// shannonEntropy returns s's Shannon entropy in bits per byte, used to tell
+// a real secret apart from an ordinary long word that happens to match a
+// generic credential-shaped pattern.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	length := float64(len(s))
+	entropy := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
 // Helper functions (simplified for performance)
 func calculateConfidence(matchCount int, text, techniqueID string) float64 {
 	baseConfidence := 0.4
@@ -168,20 +272,3 @@ func extractIOCContext(text, ioc string, contextLength int) string {
 	}
 	return extractContext(text, index, index+len(ioc), contextLength)
 }
-
-func isValidIOC(iocType, value string) bool {
-	switch iocType {
-	case "ip":
-		if strings.HasPrefix(value, "127.") || strings.HasPrefix(value, "10.") {
-			return false
-		}
-	case "domain":
-		commonDomains := []string{"microsoft.com", "google.com", "github.com", "example.com"}
-		for _, common := range commonDomains {
-			if strings.Contains(value, common) {
-				return false
-			}
-		}
-	}
-	return len(value) > 0
-}
@@ -0,0 +1,188 @@
+package threatdnacore
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// GenomeSearchResult is a genome matched by a trigram subsequence search,
+// ranked by how much of the query attack chain it shares.
+type GenomeSearchResult struct {
+	GenomeID string  `json:"genome_id"`
+	LCS      int     `json:"lcs"`
+	Jaccard  float64 `json:"jaccard"`
+}
+
+// GenomeIndex is a trigram posting list over ordered TTP-ID sequences,
+// the same technique code-search engines like Zoekt use for substrings,
+// applied here to attack chains instead of source text. It lets us answer
+// "which past campaigns share this attack chain?" instead of only
+// full-text matching on report prose.
+type GenomeIndex struct {
+	mu        sync.RWMutex
+	sequences map[string][]string            // genomeID -> ordered TTPs
+	postings  map[string]map[string]struct{} // trigram -> set of genomeIDs
+}
+
+// NewGenomeIndex creates an empty trigram index.
+func NewGenomeIndex() *GenomeIndex {
+	return &GenomeIndex{
+		sequences: make(map[string][]string),
+		postings:  make(map[string]map[string]struct{}),
+	}
+}
+
+// NewGenomeIndexFromGenomes builds an index over an already-loaded set of
+// genomes, as read back from the GenomeBuilder's store.
+func NewGenomeIndexFromGenomes(genomes []*Genome) *GenomeIndex {
+	gi := NewGenomeIndex()
+	for _, g := range genomes {
+		gi.Add(g.ID, g.TTPs)
+	}
+	return gi
+}
+
+// Add indexes a genome's ordered TTP sequence.
+func (gi *GenomeIndex) Add(genomeID string, ttps []string) {
+	gi.mu.Lock()
+	defer gi.mu.Unlock()
+
+	gi.sequences[genomeID] = ttps
+	for _, trigram := range trigrams(ttps) {
+		if gi.postings[trigram] == nil {
+			gi.postings[trigram] = make(map[string]struct{})
+		}
+		gi.postings[trigram][genomeID] = struct{}{}
+	}
+}
+
+// trigrams decomposes an ordered TTP sequence into overlapping TTP-ID
+// triples, e.g. [T1566.001 T1059.001 T1071.001] -> ["T1566.001→T1059.001→T1071.001"].
+func trigrams(ttps []string) []string {
+	if len(ttps) < 3 {
+		return nil
+	}
+	grams := make([]string, 0, len(ttps)-2)
+	for i := 0; i+3 <= len(ttps); i++ {
+		grams = append(grams, strings.Join(ttps[i:i+3], "→"))
+	}
+	return grams
+}
+
+// Search decomposes the query genome into trigrams, intersects their
+// posting lists to get candidates, verifies each with an exact ordered
+// longest-common-subsequence check, and keeps candidates whose LCS is at
+// least minLCS. Results are ranked by LCS length, then Jaccard similarity
+// of the technique sets.
+func (gi *GenomeIndex) Search(query []string, minLCS int) []GenomeSearchResult {
+	gi.mu.RLock()
+	defer gi.mu.RUnlock()
+
+	candidates := gi.candidateIDs(query)
+
+	var results []GenomeSearchResult
+	for id := range candidates {
+		seq := gi.sequences[id]
+		lcs := longestCommonSubsequence(query, seq)
+		if lcs < minLCS {
+			continue
+		}
+		results = append(results, GenomeSearchResult{
+			GenomeID: id,
+			LCS:      lcs,
+			Jaccard:  jaccardSimilarity(query, seq),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].LCS != results[j].LCS {
+			return results[i].LCS > results[j].LCS
+		}
+		return results[i].Jaccard > results[j].Jaccard
+	})
+
+	return results
+}
+
+// candidateIDs intersects the posting lists of every trigram in query. When
+// the query is too short to form a trigram (fewer than 3 TTPs), every
+// indexed genome is considered a candidate and left to the LCS check.
+func (gi *GenomeIndex) candidateIDs(query []string) map[string]struct{} {
+	queryGrams := trigrams(query)
+	if len(queryGrams) == 0 {
+		all := make(map[string]struct{}, len(gi.sequences))
+		for id := range gi.sequences {
+			all[id] = struct{}{}
+		}
+		return all
+	}
+
+	var candidates map[string]struct{}
+	for _, gram := range queryGrams {
+		posting := gi.postings[gram]
+		if candidates == nil {
+			candidates = make(map[string]struct{}, len(posting))
+			for id := range posting {
+				candidates[id] = struct{}{}
+			}
+			continue
+		}
+		for id := range candidates {
+			if _, ok := posting[id]; !ok {
+				delete(candidates, id)
+			}
+		}
+	}
+	return candidates
+}
+
+// longestCommonSubsequence returns the length of the longest ordered
+// subsequence shared by a and b, verifying that a query attack chain
+// actually reproduces (in order, not necessarily contiguously) inside a
+// candidate genome's sequence.
+func longestCommonSubsequence(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else if dp[i-1][j] > dp[i][j-1] {
+				dp[i][j] = dp[i-1][j]
+			} else {
+				dp[i][j] = dp[i][j-1]
+			}
+		}
+	}
+	return dp[len(a)][len(b)]
+}
+
+// jaccardSimilarity scores how much the unordered technique sets of a and b
+// overlap, as a tiebreaker alongside LCS length.
+func jaccardSimilarity(a, b []string) float64 {
+	setA := toSet(a)
+	setB := toSet(b)
+
+	intersection := 0
+	for ttp := range setA {
+		if _, ok := setB[ttp]; ok {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		set[item] = struct{}{}
+	}
+	return set
+}
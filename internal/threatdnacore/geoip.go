@@ -0,0 +1,138 @@
+package threatdnacore
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// GeoIPCityDBEnv and GeoIPASNDBEnv name the environment variables
+// GenomeBuilder reads the MaxMind GeoLite2-City.mmdb/GeoLite2-ASN.mmdb paths
+// from, the same way CVEEnricher's feed paths are environment-configured.
+const (
+	GeoIPCityDBEnv = "GEOIP_CITY_DB"
+	GeoIPASNDBEnv  = "GEOIP_ASN_DB"
+)
+
+// GeoIPEnricher looks up country, city, and ASN metadata for IP-type IOCs
+// against local MaxMind GeoLite2 databases. Either database may be absent,
+// in which case the corresponding lookups are skipped so offline/CI use
+// keeps working without the .mmdb files checked in.
+type GeoIPEnricher struct {
+	cityReader *geoip2.Reader
+	asnReader  *geoip2.Reader
+}
+
+// NewGeoIPEnricher opens the GeoLite2 databases at cityPath/asnPath. A path
+// that doesn't exist is tolerated (that lookup is simply skipped); only a
+// database file that exists but fails to parse returns an error.
+func NewGeoIPEnricher(cityPath, asnPath string) (*GeoIPEnricher, error) {
+	e := &GeoIPEnricher{}
+
+	if cityPath != "" {
+		if _, err := os.Stat(cityPath); err == nil {
+			reader, err := geoip2.Open(cityPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open GeoIP city database %s: %w", cityPath, err)
+			}
+			e.cityReader = reader
+		} else {
+			log.Printf("⚠️  GeoIP city database %s not found, country/city enrichment disabled", cityPath)
+		}
+	}
+
+	if asnPath != "" {
+		if _, err := os.Stat(asnPath); err == nil {
+			reader, err := geoip2.Open(asnPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open GeoIP ASN database %s: %w", asnPath, err)
+			}
+			e.asnReader = reader
+		} else {
+			log.Printf("⚠️  GeoIP ASN database %s not found, ASN enrichment disabled", asnPath)
+		}
+	}
+
+	return e, nil
+}
+
+// Close releases the underlying mmdb file handles.
+func (e *GeoIPEnricher) Close() error {
+	if e.cityReader != nil {
+		if err := e.cityReader.Close(); err != nil {
+			return err
+		}
+	}
+	if e.asnReader != nil {
+		return e.asnReader.Close()
+	}
+	return nil
+}
+
+// isIPIOCType reports whether iocType is one of the IP address variants
+// GeoIPEnricher enriches.
+func isIPIOCType(iocType string) bool {
+	switch iocType {
+	case "ip", "ipv4", "ipv6":
+		return true
+	default:
+		return false
+	}
+}
+
+// EnrichIP looks up a single IP address against the loaded databases and
+// returns the enrichment as a flat string map, ready to assign to
+// IOC.Enrichment. An empty map (not nil) is returned for an address neither
+// database has an entry for, or when no database is loaded.
+func (e *GeoIPEnricher) EnrichIP(ip string) map[string]string {
+	enrichment := make(map[string]string)
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return enrichment
+	}
+
+	if e.cityReader != nil {
+		if record, err := e.cityReader.City(addr); err == nil {
+			if name := record.Country.Names["en"]; name != "" {
+				enrichment["country"] = name
+			}
+			if record.Country.IsoCode != "" {
+				enrichment["country_iso"] = record.Country.IsoCode
+			}
+			if name := record.City.Names["en"]; name != "" {
+				enrichment["city"] = name
+			}
+		}
+	}
+
+	if e.asnReader != nil {
+		if record, err := e.asnReader.ASN(addr); err == nil {
+			if record.AutonomousSystemNumber != 0 {
+				enrichment["asn"] = "AS" + strconv.FormatUint(uint64(record.AutonomousSystemNumber), 10)
+			}
+			if record.AutonomousSystemOrganization != "" {
+				enrichment["as_org"] = record.AutonomousSystemOrganization
+			}
+		}
+	}
+
+	return enrichment
+}
+
+// EnrichIOCs annotates every ip/ipv4/ipv6 IOC in iocs with its
+// GeoIPEnricher.EnrichIP result, in place. Non-IP IOCs are left untouched.
+func (e *GeoIPEnricher) EnrichIOCs(iocs []IOC) {
+	for i := range iocs {
+		if !isIPIOCType(iocs[i].Type) {
+			continue
+		}
+		enrichment := e.EnrichIP(iocs[i].Value)
+		if len(enrichment) > 0 {
+			iocs[i].Enrichment = enrichment
+		}
+	}
+}
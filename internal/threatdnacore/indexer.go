@@ -4,23 +4,44 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"log"
+	"runtime"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/blevesearch/bleve/v2"
 )
 
+// indexBatchFlushSize is how many documents each batch accumulates before
+// being flushed to the index.
+const indexBatchFlushSize = 10
+
+// indexParallelThreshold is the genome count above which IndexBleveData
+// partitions work across a worker pool instead of indexing inline.
+const indexParallelThreshold = 100
+
 // SearchDocument is the enriched document we will store in the Bleve index.
 type SearchDocument struct {
-	Actor           string    `json:"actor"`
-	Campaign        string    `json:"campaign"`
-	TTPs            []string  `json:"ttps"`
-	Tactics         []string  `json:"tactics"`
-	Platforms       []string  `json:"platforms"`
-	Confidence      float64   `json:"confidence"`
-	FirstSeen       time.Time `json:"first_seen"`
-	LastSeen        time.Time `json:"last_seen"`
-	AllSourceText   string    `json:"all_source_text"`
-	Type            string    `json:"type"`
+	Actor         string    `json:"actor"`
+	Campaign      string    `json:"campaign"`
+	TTPs          []string  `json:"ttps"`
+	Tactics       []string  `json:"tactics"`
+	Platforms     []string  `json:"platforms"`
+	Confidence    float64   `json:"confidence"`
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+	AllSourceText string    `json:"all_source_text"`
+	Type          string    `json:"type"`
+	CVEIDs        []string  `json:"cve_id,omitempty"`
+	MaxCVSSScore  float64   `json:"cvss_score"`
+	KEVExploited  bool      `json:"kev_exploited"`
+	Detections    []string  `json:"detections,omitempty"`
+	GeoCountries  []string  `json:"geo_countries,omitempty"`
+	GeoASNs       []string  `json:"geo_asns,omitempty"`
+	// TTPSequence is the genome's TTPs, space-joined in order, analyzed with
+	// the ttp_sequence shingle analyzer so ordered technique chains (e.g.
+	// "T1078 T1053 T1547") can be matched with a phrase query.
+	TTPSequence string `json:"ttp_sequence,omitempty"`
 }
 
 // loadData reads and parses the source JSON files.
@@ -62,6 +83,12 @@ func CreateBleveIndex(indexPath string) bleve.Index {
 	docMapping.AddFieldMappingsAt("tactics", keywordFieldMapping)
 	docMapping.AddFieldMappingsAt("platforms", keywordFieldMapping)
 	docMapping.AddFieldMappingsAt("all_source_text", testFieldMapping)
+	docMapping.AddFieldMappingsAt("cve_id", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("cvss_score", bleve.NewNumericFieldMapping())
+	docMapping.AddFieldMappingsAt("kev_exploited", bleve.NewBooleanFieldMapping())
+	docMapping.AddFieldMappingsAt("detections", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("geo_countries", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("geo_asns", keywordFieldMapping)
 
 	indexMapping := bleve.NewIndexMapping()
 	indexMapping.AddDocumentMapping("genome", docMapping)
@@ -73,37 +100,56 @@ func CreateBleveIndex(indexPath string) bleve.Index {
 	return index
 }
 
-// indexData enriches and indexes the documents in batches.
-func IndexBleveData(index bleve.Index, genomes []Genome, ctiMap map[string]string) {
+// BuildSearchDocument builds the SearchDocument IndexBleveData stores for
+// genome, joining in its source reports' raw text from ctiMap. It is
+// exported so the search package's Backend implementations can build the
+// same document shape outside this package.
+func BuildSearchDocument(genome Genome, ctiMap map[string]string) SearchDocument {
+	var allText string
+	for _, sourceID := range genome.SourceIDs {
+		if text, ok := ctiMap[sourceID]; ok {
+			allText += text + "\n"
+		}
+	}
+
+	maxCVSS, _ := genome.Metadata["max_cvss"].(float64)
+	kevExploited, _ := genome.Metadata["kev_exploited"].(bool)
+	geoCountries, _ := genome.Metadata["geo_countries"].([]string)
+	geoASNs, _ := genome.Metadata["geo_asns"].([]string)
+
+	return SearchDocument{
+		Actor:         genome.Actor,
+		Campaign:      genome.Campaign,
+		TTPs:          genome.TTPs,
+		Tactics:       genome.Tactics,
+		Platforms:     genome.Platforms,
+		Confidence:    genome.Confidence,
+		FirstSeen:     genome.FirstSeen,
+		LastSeen:      genome.LastSeen,
+		AllSourceText: allText,
+		Type:          "genome",
+		CVEIDs:        genome.CVEs,
+		MaxCVSSScore:  maxCVSS,
+		KEVExploited:  kevExploited,
+		Detections:    genome.Detections,
+		GeoCountries:  geoCountries,
+		GeoASNs:       geoASNs,
+		TTPSequence:   strings.Join(genome.TTPs, " "),
+	}
+}
 
+// indexGenomeBatch indexes genomes into index in batches of batchSize,
+// reporting one Increment per document, and returns how many it indexed.
+func indexGenomeBatch(index bleve.Index, genomes []Genome, ctiMap map[string]string, batchSize int, progress Progress) int {
 	batch := index.NewBatch()
 	count := 0
 
 	for _, genome := range genomes {
-		var allText string
-		for _, sourceID := range genome.SourceIDs {
-			if text, ok := ctiMap[sourceID]; ok {
-				allText += text + "\n"
-			}
-		}
-
-		searchDoc := SearchDocument{
-			Actor:         genome.Actor,
-			Campaign:      genome.Campaign,
-			TTPs:          genome.TTPs,
-			Tactics:       genome.Tactics,
-			Platforms:     genome.Platforms,
-			Confidence:    genome.Confidence,
-			FirstSeen:     genome.FirstSeen,
-			LastSeen:      genome.LastSeen,
-			AllSourceText: allText,
-			Type:          "genome",
-		}
-
-		batch.Index(genome.ID, searchDoc)
+		batch.Index(genome.ID, BuildSearchDocument(genome, ctiMap))
 		count++
+		progress.Increment()
 
-		if count%10 == 0 {
+		if count%batchSize == 0 {
 			if err := index.Batch(batch); err != nil {
 				log.Printf("Failed to index batch: %v", err)
 			}
@@ -116,5 +162,58 @@ func IndexBleveData(index bleve.Index, genomes []Genome, ctiMap map[string]strin
 			log.Printf("Failed to index final batch: %v", err)
 		}
 	}
-	log.Printf("Successfully indexed %d documents.", count)
+
+	return count
+}
+
+// IndexBleveData enriches and indexes the documents in batches of
+// batchSize (use indexBatchFlushSize for the historical default), reporting
+// progress via progress — pass NoopProgress{} for silent operation. Large
+// genome sets partition across a worker pool, each owning its own batch so
+// index build time drops roughly linearly with cores; small sets index
+// inline.
+func IndexBleveData(index bleve.Index, genomes []Genome, ctiMap map[string]string, batchSize int, progress Progress) {
+	if batchSize <= 0 {
+		batchSize = indexBatchFlushSize
+	}
+	if progress == nil {
+		progress = NoopProgress{}
+	}
+	progress.Start(len(genomes))
+	defer progress.Finish()
+
+	if len(genomes) <= indexParallelThreshold {
+		count := indexGenomeBatch(index, genomes, ctiMap, batchSize, progress)
+		log.Printf("Successfully indexed %d documents.", count)
+		return
+	}
+
+	workers := runtime.NumCPU()
+	if workers > len(genomes) {
+		workers = len(genomes)
+	}
+	chunkSize := (len(genomes) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	total := 0
+
+	for start := 0; start < len(genomes); start += chunkSize {
+		end := start + chunkSize
+		if end > len(genomes) {
+			end = len(genomes)
+		}
+
+		wg.Add(1)
+		go func(chunk []Genome) {
+			defer wg.Done()
+			count := indexGenomeBatch(index, chunk, ctiMap, batchSize, progress)
+			mu.Lock()
+			total += count
+			mu.Unlock()
+		}(genomes[start:end])
+	}
+	wg.Wait()
+
+	log.Printf("Successfully indexed %d documents.", total)
 }
@@ -11,78 +11,231 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
 )
 
+// RulesDir is the default location analysts drop signature YAML files into.
+// It can be overridden via DataIngester.RulesDir before calling Initialize.
+const RulesDir = "data/rules"
+
+// NVDFeedDir and CISAKEVPath are the default on-disk locations for the
+// locally cached CVE enrichment data. TemplatesDir is the default location
+// for DetectionTemplate YAML files.
+const (
+	NVDFeedDir   = "data/nvd"
+	CISAKEVPath  = "data/nvd/kev.json"
+	TemplatesDir = "data/templates"
+)
+
 // DataIngester handles ingesting raw CTI data from various sources.
 type DataIngester struct {
-	parser     *CTIParser
-	htmlParser *HTMLParser
-	mu         sync.Mutex // Mutex to protect concurrent access if needed
+	parser         *CTIParser
+	htmlParser     *HTMLParser
+	sourceParsers  []SourceParser // tried in order; first CanHandle match wins
+	cveEnricher    *CVEEnricher
+	templateEngine *TemplateEngine
+	mitreGraph     *MITREGraph
+	RulesDir       string
+	TemplatesDir   string
+	mu             sync.Mutex // Mutex to protect concurrent access if needed
 }
 
 // NewDataIngester creates a new DataIngester instance.
 func NewDataIngester() *DataIngester {
-	return &DataIngester{}
+	return &DataIngester{RulesDir: RulesDir}
 }
 
-// Initialize loads the MITRE ATT&CK data and initializes the CTI parser.
+// Initialize loads the MITRE ATT&CK data, the externalized signature rules,
+// and initializes the CTI parser.
 func (di *DataIngester) Initialize() error {
 	log.Println("🚀 Initializing CTI Parser...")
 
-	mitreData, err := di.loadMitreAttackData("data/enterprise-attack.json")
+	mitreData, graph, err := di.loadMitreAttackData("data/enterprise-attack.json")
 	if err != nil {
 		log.Printf("⚠️  Warning: could not load from 'data/enterprise-attack.json'. Falling back to sample data. Error: %v", err)
 		mitreData = di.loadSampleMitreData() // Fallback to sample data
+		graph = newMITREGraph()
+	}
+	di.mitreGraph = graph
+
+	if di.RulesDir == "" {
+		di.RulesDir = RulesDir
+	}
+	rules, err := LoadFromDir(di.RulesDir)
+	if err != nil {
+		return fmt.Errorf("failed to load signature rules: %w", err)
+	}
+
+	di.parser = NewCTIParser(mitreData, rules)
+	di.htmlParser = NewHTMLParser(rules)
+	di.sourceParsers = []SourceParser{
+		&mispSourceParser{},
+		&rssSourceParser{hp: di.htmlParser},
+		&pdfSourceParser{hp: di.htmlParser},
+		&htmlSourceParser{hp: di.htmlParser}, // .html/.htm files, registered last
+	}
+
+	cveEnricher, err := NewCVEEnricher(NVDFeedDir, CISAKEVPath)
+	if err != nil {
+		return fmt.Errorf("failed to initialize CVE enricher: %w", err)
+	}
+	di.cveEnricher = cveEnricher
+
+	if di.TemplatesDir == "" {
+		di.TemplatesDir = TemplatesDir
+	}
+	templateEngine, err := LoadTemplateEngine(di.TemplatesDir)
+	if err != nil {
+		return fmt.Errorf("failed to load detection templates: %w", err)
 	}
+	di.templateEngine = templateEngine
 
-	di.parser = NewCTIParser(mitreData)
-	di.htmlParser = NewHTMLParser()
 	return nil
 }
 
-// IngestDirectory processes all HTML files in a given directory.
+// MITREGraph returns the relationship graph built from the MITRE ATT&CK
+// bundle during Initialize, for TechniquesUsedBy/MitigationsFor/
+// SubtechniquesOf-style queries. It is nil until Initialize has run.
+func (di *DataIngester) MITREGraph() *MITREGraph {
+	return di.mitreGraph
+}
+
+// IngestDirectory processes every file under dirPath (recursively) using a
+// background context, the default worker pool size, and NoopProgress. Use
+// IngestDirectoryContext directly for cancellation, a custom concurrency, or
+// progress reporting.
 func (di *DataIngester) IngestDirectory(dirPath string) ([]CTIRecord, error) {
-	var records []CTIRecord
-	log.Printf("📁 Found %d files to process", di.countHtmlFiles(dirPath))
+	return di.IngestDirectoryContext(context.Background(), dirPath, 0, NoopProgress{})
+}
+
+// IngestDirectoryContext recursively walks dirPath and ingests every file it
+// finds through a bounded worker pool (concurrency workers; <1 defaults to
+// runtime.NumCPU()), dispatching each to the first registered SourceParser
+// that claims it (IngestFileFastContext). A file no parser can handle is
+// logged and skipped rather than failing the whole directory. progress gets
+// one Increment per file attempted, matching BuildGenomesFromRecords'
+// convention — pass NoopProgress{} for silent operation. ctx cancellation
+// stops dispatching new files and causes already-returned records to be
+// returned alongside ctx.Err().
+func (di *DataIngester) IngestDirectoryContext(ctx context.Context, dirPath string, concurrency int, progress Progress) ([]CTIRecord, error) {
+	if concurrency < 1 {
+		concurrency = runtime.NumCPU()
+	}
+	if progress == nil {
+		progress = NoopProgress{}
+	}
 
-	files, err := os.ReadDir(dirPath)
+	var paths []string
+	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
-	}
-
-	processedCount := 0
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".html") {
-			processedCount++
-			log.Printf("📄 Processing file %d/%d: %s", processedCount, di.countHtmlFiles(dirPath), file.Name())
-			fullPath := filepath.Join(dirPath, file.Name())
-			record, err := di.IngestFileFast(fullPath)
-			if err != nil {
-				log.Printf("❌ Error ingesting file %s: %v", fullPath, err)
-				continue
+		return nil, fmt.Errorf("failed to walk directory %s: %w", dirPath, err)
+	}
+
+	log.Printf("📁 Found %d files to process", len(paths))
+
+	type ingestResult struct {
+		record *CTIRecord
+		err    error
+		path   string
+	}
+
+	pathCh := make(chan string)
+	resultCh := make(chan ingestResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range pathCh {
+				record, err := di.IngestFileFastContext(ctx, path)
+				select {
+				case resultCh <- ingestResult{record: record, err: err, path: path}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(pathCh)
+		for _, path := range paths {
+			select {
+			case pathCh <- path:
+			case <-ctx.Done():
+				return
 			}
-			records = append(records, *record)
 		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	progress.Start(len(paths))
+	defer progress.Finish()
+
+	var records []CTIRecord
+	for res := range resultCh {
+		progress.Increment()
+		if res.err != nil {
+			log.Printf("❌ Error ingesting file %s: %v", res.path, res.err)
+			continue
+		}
+		records = append(records, *res.record)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return records, err
+	}
 	return records, nil
 }
 
-// IngestFileFast processes a single file quickly.
+// IngestFileFast processes a single file quickly, dispatching it to the
+// first registered SourceParser whose CanHandle matches.
 func (di *DataIngester) IngestFileFast(filePath string) (*CTIRecord, error) {
+	return di.IngestFileFastContext(context.Background(), filePath)
+}
+
+// IngestFileFastContext is IngestFileFast with a caller-supplied context,
+// so callers ingesting many files concurrently (IngestDirectory's worker
+// pool) can cancel in-flight parses.
+func (di *DataIngester) IngestFileFastContext(ctx context.Context, filePath string) (*CTIRecord, error) {
 	log.Printf("📄 Processing: %s", filePath)
 
 	// Ensure parser is initialized before use
-	if di.parser == nil || di.htmlParser == nil {
-		return nil, fmt.Errorf("CTI parser or HTML parser not initialized. Call Initialize() first.")
+	if di.parser == nil || len(di.sourceParsers) == 0 {
+		return nil, fmt.Errorf("CTI parser not initialized. Call Initialize() first.")
+	}
+
+	header := readFileHeader(filePath)
+	var chosen SourceParser
+	for _, sp := range di.sourceParsers {
+		if sp.CanHandle(filePath, header) {
+			chosen = sp
+			break
+		}
+	}
+	if chosen == nil {
+		return nil, fmt.Errorf("no registered SourceParser can handle %s", filePath)
 	}
 
-	records, err := di.htmlParser.ParseHTMLReportFast(filePath)
+	records, err := chosen.Parse(ctx, filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML report: %w", err)
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
 	}
 
 	if len(records) == 0 {
@@ -94,29 +247,52 @@ func (di *DataIngester) IngestFileFast(filePath string) (*CTIRecord, error) {
 	log.Println("🔍 Extracting TTPs and IOCs...")
 	di.parser.ProcessCTIRecord(record)
 
+	if di.cveEnricher != nil {
+		record.CVEs = di.cveEnricher.EnrichAll(record.IOCs)
+		if len(record.CVEs) > 0 {
+			log.Printf("🩹 Enriched %d CVEs", len(record.CVEs))
+		}
+	}
+
+	if di.templateEngine != nil {
+		record.Detections = di.templateEngine.Evaluate(record)
+		if len(record.Detections) > 0 {
+			log.Printf("🎯 Matched %d detection templates", len(record.Detections))
+		}
+	}
+
 	return record, nil
 }
 
-// HTMLParser handles HTML document parsing with optimizations
+// htmlSourceParser adapts HTMLParser to the SourceParser interface. Every
+// DataIngester registers it last, and it claims only .html/.htm paths, so a
+// file of any other extension that no earlier parser claims is not ingested.
+type htmlSourceParser struct {
+	hp *HTMLParser
+}
+
+func (p *htmlSourceParser) CanHandle(path string, header []byte) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".html") || strings.HasSuffix(lower, ".htm")
+}
+
+func (p *htmlSourceParser) Parse(ctx context.Context, path string) ([]CTIRecord, error) {
+	return p.hp.ParseHTMLReportFast(path)
+}
+
+// HTMLParser handles HTML document parsing with optimizations. Actor and
+// campaign signatures come from the externalized RuleSet so analysts can
+// add new aliases without recompiling.
 type HTMLParser struct {
-	actorPatterns    []*regexp.Regexp
-	campaignPatterns []*regexp.Regexp
-	datePatterns     []*regexp.Regexp
-	maxTextLength    int
+	rules         *RuleSet
+	datePatterns  []*regexp.Regexp
+	maxTextLength int
 }
 
-// NewHTMLParser creates an optimized HTML parser
-func NewHTMLParser() *HTMLParser {
+// NewHTMLParser creates an optimized HTML parser backed by rules.
+func NewHTMLParser(rules *RuleSet) *HTMLParser {
 	return &HTMLParser{
-		actorPatterns: []*regexp.Regexp{
-			regexp.MustCompile(`(?i)(APT\d+|Lazarus|Fancy Bear|Cozy Bear|Sandworm|Midnight Blizzard|Equation Group|Carbanak|FIN\d+|Turla|Silence|TA\d+|UNC\d+|OutSteel|SaintBot)`),
-			regexp.MustCompile(`(?i)threat\s+actor[s]?:?\s*([A-Z][a-zA-Z0-9\s-]{3,30})`),
-			regexp.MustCompile(`(?i)group[s]?:?\s*([A-Z][a-zA-Z0-9\s-]{3,30})`),
-		},
-		campaignPatterns: []*regexp.Regexp{
-			regexp.MustCompile(`(?i)campaign[s]?:?\s*([A-Z][a-zA-Z0-9\s-]{3,50})`),
-			regexp.MustCompile(`(?i)operation[s]?:?\s*([A-Z][a-zA-Z0-9\s-]{3,50})`),
-		},
+		rules: rules,
 		datePatterns: []*regexp.Regexp{
 			regexp.MustCompile(`\d{4}-\d{2}-\d{2}`),
 			regexp.MustCompile(`\d{1,2}/\d{1,2}/\d{4}`),
@@ -167,21 +343,21 @@ func (hp *HTMLParser) ParseHTMLReportFast(filepath string) ([]CTIRecord, error)
 	}
 
 	// Extract threat actor information
-	if actor := extractActorFromText(text, hp.actorPatterns); actor != "" {
+	if actor, _ := hp.rules.MatchFirst(CategoryActor, text); actor != "" {
 		record.Actor = actor
 		log.Printf("🎭 Found actor: %s", actor)
 	}
 
 	// Extract campaign information
-	if campaign := extractCampaignFromText(text, hp.campaignPatterns); campaign != "" {
+	if campaign, _ := hp.rules.MatchFirst(CategoryCampaign, text); campaign != "" {
 		record.Campaign = campaign
 		log.Printf("🚀 Found campaign: %s", campaign)
 	}
 
 	// If no actor found in text, try filename/title
 	if record.Actor == "" {
-		record.Actor = extractActorFromTitle(title + " " + filepath)
-		if record.Actor != "" {
+		if actor, _ := hp.rules.MatchFirst(CategoryActor, title+" "+filepath); actor != "" {
+			record.Actor = actor
 			log.Printf("🎭 Extracted actor from title: %s", record.Actor)
 		}
 	}
@@ -194,6 +370,30 @@ func (hp *HTMLParser) ParseHTMLReportFast(filepath string) ([]CTIRecord, error)
 	}
 }
 
+// buildTextRecord applies the same actor/campaign signature matching
+// ParseHTMLReportFast does, over text that a non-HTML SourceParser (PDF,
+// RSS item body) has already extracted, so every source format feeds the
+// extractor pipeline identically once it has plain text. The record's date
+// is the one the caller passes in; no date is matched from text here.
+func (hp *HTMLParser) buildTextRecord(id, source, text string, date time.Time, tags []string) CTIRecord {
+	record := CTIRecord{
+		ID:      id,
+		Source:  source,
+		Date:    date,
+		RawText: text,
+		Tags:    tags,
+	}
+
+	if actor, _ := hp.rules.MatchFirst(CategoryActor, text); actor != "" {
+		record.Actor = actor
+	}
+	if campaign, _ := hp.rules.MatchFirst(CategoryCampaign, text); campaign != "" {
+		record.Campaign = campaign
+	}
+
+	return record
+}
+
 // Quick text extraction without full DOM parsing
 func extractTextQuick(htmlContent string) string {
 	// Remove script and style content
@@ -244,90 +444,74 @@ func extractDateFromText(text string, patterns []*regexp.Regexp) time.Time {
 	return time.Now()
 }
 
-func extractActorFromText(text string, patterns []*regexp.Regexp) string {
-	for _, pattern := range patterns {
-		matches := pattern.FindStringSubmatch(text)
-		if len(matches) > 1 {
-			return strings.TrimSpace(matches[1])
-		}
-	}
-	return ""
-}
-
-func extractCampaignFromText(text string, patterns []*regexp.Regexp) string {
-	for _, pattern := range patterns {
-		matches := pattern.FindStringSubmatch(text)
-		if len(matches) > 1 {
-			return strings.TrimSpace(matches[1])
-		}
-	}
-	return ""
-}
-
-func extractActorFromTitle(title string) string {
-	actorPattern := regexp.MustCompile(`(?i)(APT\d+|Lazarus|Fancy Bear|Cozy Bear|Sandworm|Midnight Blizzard|Carbanak|FIN\d+|Turla|Silence|TA\d+|UNC\d+|OutSteel|SaintBot)`)
-	matches := actorPattern.FindStringSubmatch(title)
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	return ""
-}
-
-func (di *DataIngester) loadMitreAttackData(filePath string) (map[string]AttackTechnique, error) {
+// loadMitreAttackData parses filePath's STIX bundle into the technique
+// lookup table TechniqueExtractor matches against, and in the same pass
+// builds a MITREGraph from every intrusion-set/malware/campaign/
+// course-of-action object and "uses"/"mitigates"/"attributed-to"/
+// "subtechnique-of" relationship the bundle carries, so actor/technique/
+// mitigation graph queries don't need a second parse of the same file.
+func (di *DataIngester) loadMitreAttackData(filePath string) (map[string]AttackTechnique, *MITREGraph, error) {
 	log.Printf("Loading full MITRE ATT&CK dataset from %s...", filePath)
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read MITRE file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read MITRE file: %w", err)
 	}
 
 	var bundle MITREAttackBundle
 	if err := json.Unmarshal(data, &bundle); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal MITRE JSON: %w", err)
+		return nil, nil, fmt.Errorf("failed to unmarshal MITRE JSON: %w", err)
 	}
 
 	techniques := make(map[string]AttackTechnique)
-	for _, obj := range bundle.Objects {
-		// We only care about attack patterns (techniques and sub-techniques)
-		if obj.Type != "attack-pattern" {
-			continue
-		}
+	graph := newMITREGraph()
+	edgeCount := 0
 
-		var techniqueID string
-		for _, ref := range obj.ExternalReferences {
-			if ref.SourceName == "mitre-attack" {
-				techniqueID = ref.ExternalID
-				break
+	for _, obj := range bundle.Objects {
+		switch obj.Type {
+		case "attack-pattern":
+			var techniqueID string
+			for _, ref := range obj.ExternalReferences {
+				if ref.SourceName == "mitre-attack" {
+					techniqueID = ref.ExternalID
+					break
+				}
+			}
+			// Skip if it doesn't have a standard technique ID
+			if techniqueID == "" {
+				continue
 			}
-		}
-
-		// Skip if it doesn't have a standard technique ID
-		if techniqueID == "" {
-			continue
-		}
 
-		var tactics []string
-		for _, phase := range obj.KillChainPhases {
-			if phase.KillChainName == "mitre-attack" {
-				tactics = append(tactics, phase.PhaseName)
+			var tactics []string
+			for _, phase := range obj.KillChainPhases {
+				if phase.KillChainName == "mitre-attack" {
+					tactics = append(tactics, phase.PhaseName)
+				}
 			}
-		}
 
-		// Generate keywords from name for searching
-		keywords := strings.Split(strings.ToLower(obj.Name), " ")
-
-		techniques[techniqueID] = AttackTechnique{
-			ID:                 techniqueID,
-			Name:               obj.Name,
-			Description:        obj.Description,
-			Keywords:           keywords,
-			Platforms:          obj.Platforms,
-			Tactics:            tactics,
-			ExternalReferences: obj.ExternalReferences,
+			// Generate keywords from name for searching
+			keywords := strings.Split(strings.ToLower(obj.Name), " ")
+
+			techniques[techniqueID] = AttackTechnique{
+				ID:                 techniqueID,
+				Name:               obj.Name,
+				Description:        obj.Description,
+				Keywords:           keywords,
+				Platforms:          obj.Platforms,
+				Tactics:            tactics,
+				ExternalReferences: obj.ExternalReferences,
+			}
+			graph.techniqueIDByRef[obj.ID] = techniqueID
+			graph.refByTechniqueID[techniqueID] = obj.ID
+		case "intrusion-set", "malware", "campaign", "course-of-action":
+			graph.nameByRef[obj.ID] = obj.Name
+		case "relationship":
+			graph.addEdge(obj.RelationshipType, obj.SourceRef, obj.TargetRef)
+			edgeCount++
 		}
 	}
 
-	log.Printf("Loaded %d MITRE techniques from file.", len(techniques))
-	return techniques, nil
+	log.Printf("Loaded %d MITRE techniques and %d relationship edges from file.", len(techniques), edgeCount)
+	return techniques, graph, nil
 }
 
 func (di *DataIngester) loadSampleMitreData() map[string]AttackTechnique {
@@ -354,16 +538,3 @@ func generateRecordID(filePath string) string {
 	return fmt.Sprintf("%x", hash)
 }
 
-func (di *DataIngester) countHtmlFiles(dirPath string) int {
-	count := 0
-	filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if !d.IsDir() && strings.HasSuffix(d.Name(), ".html") {
-			count++
-		}
-		return nil
-	})
-	return count
-}
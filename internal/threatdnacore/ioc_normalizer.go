@@ -0,0 +1,162 @@
+package threatdnacore
+
+import (
+	"net/netip"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// IOCNormalizer refangs obfuscated indicators (as commonly written in vendor
+// CTI reports) before extraction, then validates and canonicalizes each
+// match so downstream consumers get deduplicated, structurally sound IOCs
+// instead of raw regex matches.
+type IOCNormalizer struct{}
+
+// NewIOCNormalizer creates an IOCNormalizer. It holds no state today but is
+// constructed like the repo's other Rule/Template engines so it can grow
+// configuration (e.g. custom refang rules) without changing call sites.
+func NewIOCNormalizer() *IOCNormalizer {
+	return &IOCNormalizer{}
+}
+
+var zeroWidthStripper = strings.NewReplacer("\u200b", "", "\u200c", "", "\u200d", "", "\uFEFF", "")
+
+var refangPatterns = []struct {
+	pattern *regexp.Regexp
+	repl    string
+}{
+	{regexp.MustCompile(`\[\.\]|\(\.\)|\{\.\}`), "."},
+	{regexp.MustCompile(`(?i)\[dot\]|\(dot\)`), "."},
+	{regexp.MustCompile(`\[:\]`), ":"},
+	{regexp.MustCompile(`(?i)hxxps`), "https"},
+	{regexp.MustCompile(`(?i)hxxp`), "http"},
+	{regexp.MustCompile(`(?i)\[at\]|\(at\)`), "@"},
+}
+
+// Refang rewrites common obfuscations (hxxp, [.], (dot), zero-width
+// characters) back into their plain form so extraction regexes can match
+// indicators like "hxxp://evil[.]com" or "1.1.1[.]1".
+func (n *IOCNormalizer) Refang(text string) string {
+	text = zeroWidthStripper.Replace(text)
+	for _, rp := range refangPatterns {
+		text = rp.pattern.ReplaceAllString(text, rp.repl)
+	}
+	return text
+}
+
+// Validate reports whether value is a structurally valid, public-facing IOC
+// of the given type.
+func (n *IOCNormalizer) Validate(iocType, value string) bool {
+	switch iocType {
+	case "ip":
+		return validPublicIP(value)
+	case "domain":
+		return validPublicDomain(value)
+	case "hash":
+		return validHash(value)
+	case "url":
+		return validURL(value)
+	case "email":
+		return emailPattern.MatchString(value)
+	default:
+		return value != ""
+	}
+}
+
+// Canonicalize rewrites value into its stable, dedupe-friendly form:
+// domains are lowercased and punycode-encoded, hashes/emails are
+// lowercased, and URL query parameters are sorted.
+func (n *IOCNormalizer) Canonicalize(iocType, value string) string {
+	switch iocType {
+	case "domain":
+		if ascii, err := idna.ToASCII(strings.ToLower(value)); err == nil {
+			return ascii
+		}
+		return strings.ToLower(value)
+	case "hash", "email":
+		return strings.ToLower(value)
+	case "url":
+		return canonicalizeURL(value)
+	default:
+		return value
+	}
+}
+
+func validPublicIP(value string) bool {
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return false
+	}
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return false
+	}
+	return true
+}
+
+func validPublicDomain(value string) bool {
+	if !strings.Contains(value, ".") {
+		return false
+	}
+	ascii, err := idna.ToASCII(strings.ToLower(value))
+	if err != nil {
+		return false
+	}
+	suffix, _ := publicsuffix.PublicSuffix(ascii)
+	return suffix != "" && suffix != ascii
+}
+
+func validHash(value string) bool {
+	switch len(value) {
+	case 32, 40, 64: // MD5, SHA1, SHA256
+		return isHex(value)
+	default:
+		return false
+	}
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
+
+func validURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return u.Scheme != "" && u.Host != ""
+}
+
+var emailPattern = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+
+func canonicalizeURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+	u.Host = strings.ToLower(u.Host)
+	if u.RawQuery != "" {
+		query := u.Query()
+		keys := make([]string, 0, len(query))
+		for k := range query {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		sorted := make(url.Values, len(query))
+		for _, k := range keys {
+			sorted[k] = query[k]
+		}
+		u.RawQuery = sorted.Encode()
+	}
+	return u.String()
+}
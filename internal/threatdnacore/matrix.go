@@ -0,0 +1,220 @@
+package threatdnacore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/kshedden/gonpy"
+	"golang.org/x/crypto/blake2b"
+)
+
+// MatrixFilter narrows which genomes ExportMatrix includes, and which
+// technique columns it keeps. A zero value field is not applied, so an
+// empty MatrixFilter exports every genome against every technique seen.
+type MatrixFilter struct {
+	Tactics       []string // restrict columns to techniques tagged with one of these tactics
+	ActorRegex    string   // genome.Actor must match this regexp
+	MinConfidence float64
+	Since         time.Time // genome.LastSeen must be on or after this
+	Until         time.Time // genome.FirstSeen must be on or before this
+}
+
+// matches reports whether genome satisfies every non-zero row-level field
+// of f. Tactics is a column filter and is applied separately by ExportMatrix.
+func (f MatrixFilter) matches(genome *Genome, actorRe *regexp.Regexp) bool {
+	if actorRe != nil && !actorRe.MatchString(genome.Actor) {
+		return false
+	}
+	if f.MinConfidence > 0 && genome.Confidence < f.MinConfidence {
+		return false
+	}
+	if !f.Since.IsZero() && genome.LastSeen.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && genome.FirstSeen.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// MatrixRowLabel describes one row (genome) of an ExportMatrix shard.
+type MatrixRowLabel struct {
+	GenomeID  string    `json:"genome_id"`
+	Actor     string    `json:"actor,omitempty"`
+	Campaign  string    `json:"campaign,omitempty"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// MatrixManifest is the JSON companion ExportMatrix writes alongside each
+// shard's .npy file, so downstream pandas/scikit-learn code can label the
+// matrix's rows and columns without re-parsing genome JSON.
+type MatrixManifest struct {
+	Shard   int              `json:"shard"`
+	Shards  int              `json:"shards"`
+	Shape   [2]int           `json:"shape"` // [rows, columns]
+	Columns []string         `json:"columns"` // technique IDs, canonical (sorted) order
+	Rows    []MatrixRowLabel `json:"rows"`
+}
+
+// ExportMatrix materializes the (filtered) genome collection as a sparse
+// genomes x MITRE-techniques binary presence matrix: genomes don't retain
+// per-technique confidence past aggregation (BuildGenome keeps only the
+// genome-level Confidence), so each cell is 1.0 if the genome's TTPs include
+// that technique and 0.0 otherwise. Genomes are sharded into `shards` files
+// by blake2b(genomeID) mod shards so downstream clustering/classification
+// jobs can be parallelized; shards <= 1 writes a single file. Each shard
+// gets a "<prefix>-shard<N>.npy" matrix and a matching
+// "<prefix>-shard<N>.manifest.json" label file under outDir.
+func (gb *GenomeBuilder) ExportMatrix(filter MatrixFilter, shards int, outDir, prefix string) ([]string, error) {
+	if shards < 1 {
+		shards = 1
+	}
+
+	var actorRe *regexp.Regexp
+	if filter.ActorRegex != "" {
+		re, err := regexp.Compile(filter.ActorRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -actor-regex %q: %w", filter.ActorRegex, err)
+		}
+		actorRe = re
+	}
+
+	genomes, err := gb.ListGenomes("", "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list genomes: %w", err)
+	}
+
+	matched := make([]*Genome, 0, len(genomes))
+	for _, genome := range genomes {
+		if filter.matches(genome, actorRe) {
+			matched = append(matched, genome)
+		}
+	}
+
+	tacticSet := make(map[string]bool, len(filter.Tactics))
+	for _, tactic := range filter.Tactics {
+		tacticSet[tactic] = true
+	}
+
+	columns := matrixColumns(matched, tacticSet)
+	colIndex := make(map[string]int, len(columns))
+	for i, col := range columns {
+		colIndex[col] = i
+	}
+
+	buckets := make([][]*Genome, shards)
+	for _, genome := range matched {
+		shard := int(blake2bMod(genome.ID, uint64(shards)))
+		buckets[shard] = append(buckets[shard], genome)
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory %s: %w", outDir, err)
+	}
+
+	var written []string
+	for shard, shardGenomes := range buckets {
+		npyPath := filepath.Join(outDir, fmt.Sprintf("%s-shard%d.npy", prefix, shard))
+		manifestPath := filepath.Join(outDir, fmt.Sprintf("%s-shard%d.manifest.json", prefix, shard))
+
+		if err := writeMatrixShard(npyPath, shardGenomes, columns, colIndex); err != nil {
+			return nil, fmt.Errorf("failed to write matrix shard %d: %w", shard, err)
+		}
+		if err := writeMatrixManifest(manifestPath, shard, shards, shardGenomes, columns); err != nil {
+			return nil, fmt.Errorf("failed to write manifest for shard %d: %w", shard, err)
+		}
+		written = append(written, npyPath, manifestPath)
+	}
+
+	return written, nil
+}
+
+// matrixColumns returns the canonical (sorted, deduplicated) technique ID
+// column order for genomes, restricted to tactics present in tacticSet when
+// it is non-empty.
+func matrixColumns(genomes []*Genome, tacticSet map[string]bool) []string {
+	seen := make(map[string]bool)
+	for _, genome := range genomes {
+		for i, ttp := range genome.TTPs {
+			if len(tacticSet) > 0 {
+				tactic := "unknown"
+				if i < len(genome.Tactics) {
+					tactic = genome.Tactics[i]
+				}
+				if !tacticSet[tactic] {
+					continue
+				}
+			}
+			seen[ttp] = true
+		}
+	}
+
+	columns := make([]string, 0, len(seen))
+	for ttp := range seen {
+		columns = append(columns, ttp)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// blake2bMod hashes id with blake2b and reduces it mod n, the same sharding
+// scheme the request's "lightning tool"-style --shards flag asks for.
+func blake2bMod(id string, n uint64) uint64 {
+	sum := blake2b.Sum256([]byte(id))
+	var acc uint64
+	for _, b := range sum[:8] {
+		acc = acc<<8 | uint64(b)
+	}
+	return acc % n
+}
+
+func writeMatrixShard(path string, genomes []*Genome, columns []string, colIndex map[string]int) error {
+	data := make([]float64, len(genomes)*len(columns))
+	for row, genome := range genomes {
+		for _, ttp := range genome.TTPs {
+			if col, ok := colIndex[ttp]; ok {
+				data[row*len(columns)+col] = 1.0
+			}
+		}
+	}
+
+	writer, err := gonpy.NewFileWriter(path)
+	if err != nil {
+		return err
+	}
+	writer.Shape = []int{len(genomes), len(columns)}
+	return writer.WriteFloat64(data)
+}
+
+func writeMatrixManifest(path string, shard, shards int, genomes []*Genome, columns []string) error {
+	rows := make([]MatrixRowLabel, len(genomes))
+	for i, genome := range genomes {
+		rows[i] = MatrixRowLabel{
+			GenomeID:  genome.ID,
+			Actor:     genome.Actor,
+			Campaign:  genome.Campaign,
+			FirstSeen: genome.FirstSeen,
+			LastSeen:  genome.LastSeen,
+		}
+	}
+
+	manifest := MatrixManifest{
+		Shard:   shard,
+		Shards:  shards,
+		Shape:   [2]int{len(genomes), len(columns)},
+		Columns: columns,
+		Rows:    rows,
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
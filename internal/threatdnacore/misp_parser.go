@@ -0,0 +1,150 @@
+package threatdnacore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// mispEventWrapper mirrors the top-level shape of a MISP event export:
+// {"Event": {...}}.
+type mispEventWrapper struct {
+	Event mispEvent `json:"Event"`
+}
+
+type mispEvent struct {
+	ID        string          `json:"id"`
+	Info      string          `json:"info"`
+	Date      string          `json:"date"`
+	Attribute []mispAttribute `json:"Attribute"`
+	Galaxy    []mispGalaxy    `json:"Galaxy"`
+	Tag       []mispTag       `json:"Tag"`
+}
+
+type mispAttribute struct {
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+	Comment string `json:"comment"`
+}
+
+type mispGalaxy struct {
+	Type          string              `json:"type"`
+	GalaxyCluster []mispGalaxyCluster `json:"GalaxyCluster"`
+}
+
+type mispGalaxyCluster struct {
+	Value string `json:"value"`
+}
+
+type mispTag struct {
+	Name string `json:"name"`
+}
+
+// mispAttributeIOCType maps a MISP Attribute "type" to the IOC.Type this
+// module already understands. Attribute types with no IOC equivalent are
+// skipped rather than guessed at.
+var mispAttributeIOCType = map[string]string{
+	"ip-src":    "ip",
+	"ip-dst":    "ip",
+	"domain":    "domain",
+	"hostname":  "domain",
+	"md5":       "hash",
+	"sha1":      "hash",
+	"sha256":    "hash",
+	"url":       "url",
+	"uri":       "url",
+	"email-src": "email",
+	"email-dst": "email",
+	"email":     "email",
+}
+
+// mispTechniquePattern extracts a MITRE technique ID out of a MISP
+// mitre-attack-pattern galaxy tag, e.g.
+// `misp-galaxy:mitre-attack-pattern="Phishing - T1566"` -> "T1566".
+var mispTechniquePattern = regexp.MustCompile(`(?i)mitre-attack-pattern="[^"]*-\s*(T\d{4}(?:\.\d{3})?)"`)
+
+// mispSourceParser converts MISP event JSON exports into CTIRecords:
+// Attributes become IOCs, a threat-actor Galaxy cluster becomes the
+// Actor, a campaign Galaxy cluster becomes the Campaign, and
+// mitre-attack-pattern galaxy Tags become TTPs at full confidence since
+// MISP asserted the technique directly rather than it being inferred from
+// prose.
+type mispSourceParser struct{}
+
+func (p *mispSourceParser) CanHandle(path string, header []byte) bool {
+	if !strings.HasSuffix(strings.ToLower(path), ".json") {
+		return false
+	}
+	return strings.Contains(string(header), `"Event"`)
+}
+
+func (p *mispSourceParser) Parse(ctx context.Context, path string) ([]CTIRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MISP event %s: %w", path, err)
+	}
+
+	var wrapper mispEventWrapper
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal MISP event %s: %w", path, err)
+	}
+	event := wrapper.Event
+
+	record := CTIRecord{
+		ID:      fmt.Sprintf("misp_%s", event.ID),
+		Source:  fmt.Sprintf("misp:%s", path),
+		Date:    parseMISPDate(event.Date),
+		RawText: event.Info,
+		Tags:    []string{"misp-event"},
+	}
+
+	for _, attr := range event.Attribute {
+		iocType, ok := mispAttributeIOCType[attr.Type]
+		if !ok {
+			continue
+		}
+		record.IOCs = append(record.IOCs, IOC{
+			Type:    iocType,
+			Value:   attr.Value,
+			Context: attr.Comment,
+		})
+	}
+
+	for _, galaxy := range event.Galaxy {
+		if len(galaxy.GalaxyCluster) == 0 {
+			continue
+		}
+		name := galaxy.GalaxyCluster[0].Value
+		switch {
+		case strings.Contains(galaxy.Type, "threat-actor"):
+			record.Actor = name
+		case strings.Contains(galaxy.Type, "campaign"):
+			record.Campaign = name
+		}
+	}
+
+	for _, tag := range event.Tag {
+		m := mispTechniquePattern.FindStringSubmatch(tag.Name)
+		if m == nil {
+			continue
+		}
+		record.TTPs = append(record.TTPs, TTP{
+			TechniqueID: m[1],
+			Confidence:  1.0,
+			Context:     "misp-galaxy-tag",
+		})
+	}
+
+	return []CTIRecord{record}, nil
+}
+
+func parseMISPDate(s string) time.Time {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t
+	}
+	return time.Now()
+}
@@ -0,0 +1,126 @@
+package threatdnacore
+
+import (
+	"sort"
+	"strings"
+)
+
+// MITREEdge is one typed relationship between two MITRE STIX objects, e.g.
+// an intrusion-set "uses" an attack-pattern, or a course-of-action
+// "mitigates" one.
+type MITREEdge struct {
+	Type      string // uses, mitigates, attributed-to, subtechnique-of
+	SourceRef string
+	TargetRef string
+}
+
+// MITREGraph indexes the intrusion-set/malware/campaign/course-of-action/
+// attack-pattern objects and relationship edges of an enterprise-attack.json
+// bundle by STIX ID, so callers can answer graph queries (which techniques
+// does this actor use, what mitigates this technique, what are this
+// technique's sub-techniques) without re-walking the raw bundle on every
+// call.
+type MITREGraph struct {
+	nameByRef        map[string]string // intrusion-set/malware/campaign/course-of-action STIX ID -> name
+	techniqueIDByRef map[string]string // attack-pattern STIX ID -> "T1059"
+	refByTechniqueID map[string]string // "T1059" -> attack-pattern STIX ID
+	outEdges         map[string][]MITREEdge
+	inEdges          map[string][]MITREEdge
+}
+
+// newMITREGraph returns an empty MITREGraph ready for addEdge and for its
+// name/technique lookup maps to be filled.
+func newMITREGraph() *MITREGraph {
+	return &MITREGraph{
+		nameByRef:        make(map[string]string),
+		techniqueIDByRef: make(map[string]string),
+		refByTechniqueID: make(map[string]string),
+		outEdges:         make(map[string][]MITREEdge),
+		inEdges:          make(map[string][]MITREEdge),
+	}
+}
+
+// addEdge records a directed relationship, indexed by both ends so
+// TechniquesUsedBy-style lookups and MitigationsFor-style lookups can each
+// walk from whichever side they start with.
+func (g *MITREGraph) addEdge(relType, sourceRef, targetRef string) {
+	edge := MITREEdge{Type: relType, SourceRef: sourceRef, TargetRef: targetRef}
+	g.outEdges[sourceRef] = append(g.outEdges[sourceRef], edge)
+	g.inEdges[targetRef] = append(g.inEdges[targetRef], edge)
+}
+
+// refsForName returns every STIX ID registered under a case-insensitive
+// match of name, since actors are looked up by the human-readable name
+// CTIRecord.Actor carries rather than by STIX ID.
+func (g *MITREGraph) refsForName(name string) []string {
+	var refs []string
+	for ref, candidate := range g.nameByRef {
+		if strings.EqualFold(candidate, name) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// TechniquesUsedBy returns the MITRE technique IDs linked to actor (an
+// intrusion-set, malware, or campaign name) by a "uses" relationship,
+// sorted and deduplicated.
+func (g *MITREGraph) TechniquesUsedBy(actor string) []string {
+	seen := make(map[string]bool)
+	var techniqueIDs []string
+	for _, ref := range g.refsForName(actor) {
+		for _, edge := range g.outEdges[ref] {
+			if edge.Type != "uses" {
+				continue
+			}
+			techniqueID, ok := g.techniqueIDByRef[edge.TargetRef]
+			if !ok || seen[techniqueID] {
+				continue
+			}
+			seen[techniqueID] = true
+			techniqueIDs = append(techniqueIDs, techniqueID)
+		}
+	}
+	sort.Strings(techniqueIDs)
+	return techniqueIDs
+}
+
+// MitigationsFor returns the course-of-action names that "mitigate"
+// techniqueID, sorted.
+func (g *MITREGraph) MitigationsFor(techniqueID string) []string {
+	ref, ok := g.refByTechniqueID[techniqueID]
+	if !ok {
+		return nil
+	}
+	var mitigations []string
+	for _, edge := range g.inEdges[ref] {
+		if edge.Type != "mitigates" {
+			continue
+		}
+		if name, ok := g.nameByRef[edge.SourceRef]; ok {
+			mitigations = append(mitigations, name)
+		}
+	}
+	sort.Strings(mitigations)
+	return mitigations
+}
+
+// SubtechniquesOf returns the technique IDs that are a "subtechnique-of"
+// parentID, sorted.
+func (g *MITREGraph) SubtechniquesOf(parentID string) []string {
+	ref, ok := g.refByTechniqueID[parentID]
+	if !ok {
+		return nil
+	}
+	var subIDs []string
+	for _, edge := range g.inEdges[ref] {
+		if edge.Type != "subtechnique-of" {
+			continue
+		}
+		if techniqueID, ok := g.techniqueIDByRef[edge.SourceRef]; ok {
+			subIDs = append(subIDs, techniqueID)
+		}
+	}
+	sort.Strings(subIDs)
+	return subIDs
+}
@@ -1,7 +1,7 @@
 package threatdnacore
 
 import (
-	// "log"
+	"strings"
 )
 
 // CTIParser handles parsing of different CTI formats
@@ -10,14 +10,86 @@ type CTIParser struct {
 }
 
 // NewCTIParser creates a new CTIParser instance.
-func NewCTIParser(mitreData map[string]AttackTechnique) *CTIParser {
+func NewCTIParser(mitreData map[string]AttackTechnique, rules *RuleSet) *CTIParser {
 	return &CTIParser{
-		techniqueExtractor: NewTechniqueExtractor(mitreData),
+		techniqueExtractor: NewTechniqueExtractor(mitreData, rules),
 	}
 }
 
-// ProcessCTIRecord extracts TTPs and IOCs from a CTI record's raw text.
+// ProcessCTIRecord extracts TTPs and IOCs from a CTI record's raw text, then
+// links them via linkCVEsToTTPs. CVSS/NVD enrichment of the resulting CVE
+// IOCs into record.CVEs happens later, in DataIngester, since it requires a
+// network/cache round trip this extraction step shouldn't block on.
 func (cp *CTIParser) ProcessCTIRecord(record *CTIRecord) {
 	record.TTPs = cp.techniqueExtractor.ExtractTTPs(record.RawText)
 	record.IOCs = cp.techniqueExtractor.ExtractIOCs(record.RawText)
+	linkCVEsToTTPs(record, cp.techniqueExtractor)
+}
+
+// linkCVEsToTTPs sets TTP.RelatedCVEs for any technique that occurs in the
+// same paragraph (a "\n\n"-delimited block of RawText) as a CVE IOC, so the
+// CVE<->ATT&CK association a report draws in prose survives into structured
+// output. A technique "occurs" in a paragraph the same way ExtractTTPs
+// found it in the first place — by its name, ID, or keywords, via
+// extractor.matchTerms — rather than by a second, independent literal-ID
+// search, since most prose never spells out the raw technique ID.
+func linkCVEsToTTPs(record *CTIRecord, extractor *TechniqueExtractor) {
+	if len(record.TTPs) == 0 {
+		return
+	}
+
+	var cveIDs []string
+	for _, ioc := range record.IOCs {
+		if ioc.Type == "cve" {
+			cveIDs = append(cveIDs, ioc.Value)
+		}
+	}
+	if len(cveIDs) == 0 {
+		return
+	}
+
+	for _, paragraph := range strings.Split(record.RawText, "\n\n") {
+		upper := strings.ToUpper(paragraph)
+
+		var present []string
+		for _, cveID := range cveIDs {
+			if strings.Contains(upper, strings.ToUpper(cveID)) {
+				present = append(present, cveID)
+			}
+		}
+		if len(present) == 0 {
+			continue
+		}
+
+		for i := range record.TTPs {
+			if !paragraphMentionsTechnique(upper, extractor.matchTerms(record.TTPs[i].TechniqueID)) {
+				continue
+			}
+			for _, cveID := range present {
+				if !containsString(record.TTPs[i].RelatedCVEs, cveID) {
+					record.TTPs[i].RelatedCVEs = append(record.TTPs[i].RelatedCVEs, cveID)
+				}
+			}
+		}
+	}
+}
+
+// paragraphMentionsTechnique reports whether upper (an already-uppercased
+// paragraph) contains any of a technique's match terms.
+func paragraphMentionsTechnique(upper string, terms []string) bool {
+	for _, term := range terms {
+		if strings.Contains(upper, strings.ToUpper(term)) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
 }
\ No newline at end of file
@@ -0,0 +1,51 @@
+package threatdnacore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+var pdfWhitespaceRe = regexp.MustCompile(`\s+`)
+
+// pdfSourceParser extracts CTI reports from PDF files, the overwhelmingly
+// common format threat intel vendors publish in. Once the PDF's text
+// layer is extracted it is fed through the same actor/campaign/date
+// signature matching HTMLParser applies to HTML text via buildTextRecord.
+type pdfSourceParser struct {
+	hp *HTMLParser
+}
+
+func (p *pdfSourceParser) CanHandle(path string, header []byte) bool {
+	if strings.HasSuffix(strings.ToLower(path), ".pdf") {
+		return true
+	}
+	return len(header) >= 5 && string(header[:5]) == "%PDF-"
+}
+
+func (p *pdfSourceParser) Parse(ctx context.Context, path string) ([]CTIRecord, error) {
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF %s: %w", path, err)
+	}
+	defer f.Close()
+
+	textReader, err := r.GetPlainText()
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract text from PDF %s: %w", path, err)
+	}
+	raw, err := io.ReadAll(textReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read extracted PDF text from %s: %w", path, err)
+	}
+
+	text := strings.TrimSpace(pdfWhitespaceRe.ReplaceAllString(string(raw), " "))
+	date := extractDateFromText(text, p.hp.datePatterns)
+	record := p.hp.buildTextRecord(generateID(path), fmt.Sprintf("file:%s", path), text, date, []string{"pdf-report"})
+
+	return []CTIRecord{record}, nil
+}
@@ -0,0 +1,57 @@
+package threatdnacore
+
+import (
+	"github.com/cheggaaa/pb/v3"
+)
+
+// Progress reports the advancement of a long-running batch operation
+// (genome building over record groups, Bleve indexing over documents) so
+// library users can plug in their own reporter — a terminal bar, a logger,
+// an HTTP-SSE stream for the dashboard — instead of being stuck with
+// whatever one implementation a function hard-codes.
+type Progress interface {
+	// Start announces the total unit count work is about to process.
+	Start(total int)
+	// Increment reports that one more unit has been processed.
+	Increment()
+	// Finish announces that all units have been processed.
+	Finish()
+}
+
+// NoopProgress discards all progress events. It's the default when a caller
+// doesn't pass a Progress, so batch functions don't need a nil check at
+// every call site.
+type NoopProgress struct{}
+
+func (NoopProgress) Start(int)  {}
+func (NoopProgress) Increment() {}
+func (NoopProgress) Finish()    {}
+
+// ConsoleProgress renders a terminal bar via cheggaaa/pb/v3, including
+// throughput (docs or genomes per second) and ETA.
+type ConsoleProgress struct {
+	bar *pb.ProgressBar
+}
+
+// NewConsoleProgress returns a Progress that renders a terminal bar.
+func NewConsoleProgress() *ConsoleProgress {
+	return &ConsoleProgress{}
+}
+
+func (c *ConsoleProgress) Start(total int) {
+	c.bar = pb.New(total)
+	c.bar.SetTemplateString(`{{counters . }} {{bar . }} {{percent . }} {{etime . }} {{speed . "%s/s" }} {{rtime . "ETA %s" }}`)
+	c.bar.Start()
+}
+
+func (c *ConsoleProgress) Increment() {
+	if c.bar != nil {
+		c.bar.Increment()
+	}
+}
+
+func (c *ConsoleProgress) Finish() {
+	if c.bar != nil {
+		c.bar.Finish()
+	}
+}
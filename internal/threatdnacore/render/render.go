@@ -0,0 +1,208 @@
+// Package render formats genome listings and stats for the CLI, the way
+// crowdsec's cscli renders `alerts`/`decisions`: the same data shaped as an
+// aligned table for a terminal, CSV for spreadsheets, or raw JSON for piping
+// into jq. This replaces ad-hoc emoji-decorated log lines with output a
+// script can parse.
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+
+	"threatdna/internal/threatdnacore"
+)
+
+// Format selects how RenderGenomes/RenderStats shape their output.
+type Format string
+
+const (
+	FormatHuman Format = "human"
+	FormatTable Format = "table"
+	FormatCSV   Format = "csv"
+	FormatJSON  Format = "json"
+)
+
+// ParseFormat validates a --output flag value, defaulting empty strings to
+// FormatHuman.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatHuman:
+		return FormatHuman, nil
+	case FormatTable, FormatCSV, FormatJSON:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("unknown output format %q: must be human, table, csv, or json", s)
+	}
+}
+
+// RenderGenomes renders genomes per format. In human/table mode it prints an
+// aligned ID/Actor/Campaign/TTPs/Confidence/FirstSeen/LastSeen table; machine
+// additionally appends SourceCount and IOCCount columns in table and csv
+// mode. json emits the raw slice, unfiltered by machine.
+func RenderGenomes(genomes []*threatdnacore.Genome, format Format, machine bool) ([]byte, error) {
+	switch format {
+	case FormatHuman, FormatTable:
+		return renderGenomeTable(genomes, machine), nil
+	case FormatCSV:
+		return renderGenomeCSV(genomes, machine)
+	case FormatJSON:
+		return json.MarshalIndent(genomes, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func genomeHeader(machine bool) []string {
+	header := []string{"ID", "Actor", "Campaign", "TTPs", "Confidence", "First Seen", "Last Seen"}
+	if machine {
+		header = append(header, "Sources", "IOCs")
+	}
+	return header
+}
+
+func genomeRow(genome *threatdnacore.Genome, machine bool) []string {
+	row := []string{
+		genome.ID,
+		genome.Actor,
+		genome.Campaign,
+		strconv.Itoa(len(genome.TTPs)),
+		strconv.FormatFloat(genome.Confidence, 'f', 2, 64),
+		genome.FirstSeen.Format("2006-01-02"),
+		genome.LastSeen.Format("2006-01-02"),
+	}
+	if machine {
+		row = append(row, strconv.Itoa(genome.SourceCount), strconv.Itoa(genome.IOCCount))
+	}
+	return row
+}
+
+func renderGenomeTable(genomes []*threatdnacore.Genome, machine bool) []byte {
+	var buf bytes.Buffer
+	table := tablewriter.NewWriter(&buf)
+	table.SetHeader(genomeHeader(machine))
+	for _, genome := range genomes {
+		table.Append(genomeRow(genome, machine))
+	}
+	table.Render()
+	return buf.Bytes()
+}
+
+func renderGenomeCSV(genomes []*threatdnacore.Genome, machine bool) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(genomeHeader(machine)); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, genome := range genomes {
+		if err := w.Write(genomeRow(genome, machine)); err != nil {
+			return nil, fmt.Errorf("failed to write CSV row for genome %s: %w", genome.ID, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderStats renders a GenomeStats summary per format. human/table mode
+// renders the scalar summary plus top-TTPs and severity-frequency breakdowns
+// as separate tables; csv flattens the same rows to a single metric/value
+// sheet; json emits the raw struct, including the full categorical
+// breakdowns table mode omits for brevity.
+func RenderStats(stats *threatdnacore.GenomeStats, format Format) ([]byte, error) {
+	switch format {
+	case FormatHuman, FormatTable:
+		return renderStatsTable(stats), nil
+	case FormatCSV:
+		return renderStatsCSV(stats)
+	case FormatJSON:
+		return json.MarshalIndent(stats, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func statsSummaryRows(stats *threatdnacore.GenomeStats) [][]string {
+	return [][]string{
+		{"Total Genomes", strconv.Itoa(stats.TotalGenomes)},
+		{"Unique Actors", strconv.Itoa(len(stats.ByActor))},
+		{"Unique Campaigns", strconv.Itoa(len(stats.ByCampaign))},
+		{"Avg Genome Length", strconv.FormatFloat(stats.AvgGenomeLength, 'f', 1, 64)},
+		{"Min Confidence", strconv.FormatFloat(stats.MinConfidence, 'f', 2, 64)},
+		{"Max Confidence", strconv.FormatFloat(stats.MaxConfidence, 'f', 2, 64)},
+		{"Avg Confidence", strconv.FormatFloat(stats.AvgConfidence, 'f', 2, 64)},
+	}
+}
+
+func renderStatsTable(stats *threatdnacore.GenomeStats) []byte {
+	var buf bytes.Buffer
+
+	summary := tablewriter.NewWriter(&buf)
+	summary.SetHeader([]string{"Metric", "Value"})
+	summary.AppendBulk(statsSummaryRows(stats))
+	summary.Render()
+
+	if len(stats.TopTTPs) > 0 {
+		buf.WriteString("\nTop TTPs:\n")
+		ttps := tablewriter.NewWriter(&buf)
+		ttps.SetHeader([]string{"Technique ID", "Genomes"})
+		for _, ttp := range stats.TopTTPs {
+			ttps.Append([]string{ttp.TechniqueID, strconv.Itoa(ttp.Count)})
+		}
+		ttps.Render()
+	}
+
+	if len(stats.SeverityFrequency) > 0 {
+		buf.WriteString("\nSeverity Frequency:\n")
+		severity := tablewriter.NewWriter(&buf)
+		severity.SetHeader([]string{"Severity", "Genomes"})
+		for _, bucket := range []string{
+			threatdnacore.SeverityCritical,
+			threatdnacore.SeverityHigh,
+			threatdnacore.SeverityMedium,
+			threatdnacore.SeverityLow,
+			threatdnacore.SeverityUnscored,
+		} {
+			if count, ok := stats.SeverityFrequency[bucket]; ok {
+				severity.Append([]string{bucket, strconv.Itoa(count)})
+			}
+		}
+		severity.Render()
+	}
+
+	return buf.Bytes()
+}
+
+func renderStatsCSV(stats *threatdnacore.GenomeStats) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"metric", "value"}); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, row := range statsSummaryRows(stats) {
+		if err := w.Write(row); err != nil {
+			return nil, fmt.Errorf("failed to write CSV row %q: %w", row[0], err)
+		}
+	}
+	for _, ttp := range stats.TopTTPs {
+		if err := w.Write([]string{"ttp:" + ttp.TechniqueID, strconv.Itoa(ttp.Count)}); err != nil {
+			return nil, fmt.Errorf("failed to write CSV row for TTP %s: %w", ttp.TechniqueID, err)
+		}
+	}
+	for bucket, count := range stats.SeverityFrequency {
+		if err := w.Write([]string{"severity:" + bucket, strconv.Itoa(count)}); err != nil {
+			return nil, fmt.Errorf("failed to write CSV row for severity %s: %w", bucket, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,366 @@
+package threatdnacore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severity buckets a genome's risk weight falls into, shared by RiskReport
+// and GenomeStats.SeverityFrequency so both draw from the same scale.
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityUnscored = "unscored"
+)
+
+// ttpImpactWeights scores a MITRE tactic on the same 0-10 scale as CVSS, for
+// genomes whose TTPs carry no enriched CVE. Tactics closer to an attacker's
+// end goal (impact, exfiltration, lateral movement) weight higher than
+// reconnaissance-stage activity. Techniques not tied to any of these tactics
+// fall back to ttpImpactDefaultWeight.
+var ttpImpactWeights = map[string]float64{
+	"impact":               9.0,
+	"exfiltration":         8.0,
+	"lateral-movement":     7.5,
+	"credential-access":    7.0,
+	"privilege-escalation": 7.0,
+	"command-and-control":  6.5,
+	"initial-access":       6.5,
+	"persistence":          6.0,
+	"defense-evasion":      6.0,
+	"execution":            5.5,
+	"collection":           5.0,
+	"discovery":            4.0,
+	"resource-development": 3.0,
+	"reconnaissance":       3.0,
+}
+
+// ttpImpactDefaultWeight is used for a tactic absent from ttpImpactWeights
+// (an "unknown" tactic, or a new one MITRE has added since this table was
+// written).
+const ttpImpactDefaultWeight = 5.0
+
+// severityBucket maps a 0-10 severity weight (a CVSS score or a
+// ttpImpactWeights value) onto the same bucket names DetectionTemplate
+// severities use.
+func severityBucket(weight float64) string {
+	switch {
+	case weight >= 9.0:
+		return SeverityCritical
+	case weight >= 7.0:
+		return SeverityHigh
+	case weight >= 4.0:
+		return SeverityMedium
+	case weight > 0:
+		return SeverityLow
+	default:
+		return SeverityUnscored
+	}
+}
+
+// GenomeSeverity derives a genome's risk weight and bucket: its enriched
+// MaxCVSSScore when it has one, otherwise the highest ttpImpactWeights entry
+// among its tactics.
+func GenomeSeverity(genome *Genome) (float64, string) {
+	if genome.MaxCVSSScore > 0 {
+		return genome.MaxCVSSScore, severityBucket(genome.MaxCVSSScore)
+	}
+
+	weight := 0.0
+	for _, tactic := range genome.Tactics {
+		w, ok := ttpImpactWeights[strings.ToLower(tactic)]
+		if !ok {
+			w = ttpImpactDefaultWeight
+		}
+		if w > weight {
+			weight = w
+		}
+	}
+	return weight, severityBucket(weight)
+}
+
+// topExposureLimit bounds how many platforms/actors RiskReport ranks, for
+// the same long-tail reason as topTTPLimit.
+const topExposureLimit = 10
+
+// ActorRisk is one actor's breakdown in a RiskReport.
+type ActorRisk struct {
+	Actor           string         `json:"actor"`
+	GenomeCount     int            `json:"genome_count"`
+	SeverityCounts  map[string]int `json:"severity_counts"`
+	MaxCVSSScore    float64        `json:"max_cvss_score"`
+	KEVExploited    int            `json:"kev_exploited_count"`
+	LastSeen        time.Time      `json:"last_seen"`
+}
+
+// PlatformRisk is one platform's breakdown in a RiskReport.
+type PlatformRisk struct {
+	Platform        string         `json:"platform"`
+	GenomeCount     int            `json:"genome_count"`
+	SeverityCounts  map[string]int `json:"severity_counts"`
+	ExposureScore   float64        `json:"exposure_score"`
+}
+
+// PlatformExposure is one entry of RiskReport.TopExposedPlatforms.
+type PlatformExposure struct {
+	Platform      string  `json:"platform"`
+	ExposureScore float64 `json:"exposure_score"`
+	GenomeCount   int     `json:"genome_count"`
+}
+
+// ActorActivity is one entry of RiskReport.RecentlyActiveActors.
+type ActorActivity struct {
+	Actor       string    `json:"actor"`
+	LastSeen    time.Time `json:"last_seen"`
+	GenomeCount int       `json:"genome_count"`
+}
+
+// RiskReport is the structured output of RiskReporter.GenerateReport: a
+// vulnerability-report-style severity breakdown of a genome collection,
+// grouped by actor and platform.
+type RiskReport struct {
+	GeneratedAt          time.Time          `json:"generated_at"`
+	Filter               ExportFilter       `json:"filter"`
+	TotalGenomes         int                `json:"total_genomes"`
+	SeverityTotals       map[string]int     `json:"severity_totals"`
+	ByActor              []*ActorRisk       `json:"by_actor"`
+	ByPlatform           []*PlatformRisk    `json:"by_platform"`
+	TopExposedPlatforms  []PlatformExposure `json:"top_exposed_platforms"`
+	RecentlyActiveActors []ActorActivity    `json:"recently_active_actors"`
+}
+
+// RiskReporter produces RiskReports from a GenomeBuilder's store, modeled on
+// the per-asset/per-host breakdowns a vulnerability scanner report gives a
+// security team.
+type RiskReporter struct {
+	builder *GenomeBuilder
+}
+
+// NewRiskReporter creates a RiskReporter reading genomes from builder.
+func NewRiskReporter(builder *GenomeBuilder) *RiskReporter {
+	return &RiskReporter{builder: builder}
+}
+
+// GenerateReport lists the genomes matching filter and aggregates them into
+// a RiskReport. A zero-value filter reports on the whole collection.
+func (r *RiskReporter) GenerateReport(filter ExportFilter) (*RiskReport, error) {
+	genomes, err := r.builder.ListGenomes("", "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list genomes for risk report: %w", err)
+	}
+
+	report := &RiskReport{
+		GeneratedAt:    time.Now(),
+		Filter:         filter,
+		SeverityTotals: make(map[string]int),
+	}
+
+	actors := make(map[string]*ActorRisk)
+	platforms := make(map[string]*PlatformRisk)
+
+	for _, genome := range genomes {
+		if !filter.Matches(genome) {
+			continue
+		}
+		report.TotalGenomes++
+
+		weight, bucket := GenomeSeverity(genome)
+		report.SeverityTotals[bucket]++
+
+		actor := genome.Actor
+		if actor == "" {
+			actor = "unknown"
+		}
+		ar, ok := actors[actor]
+		if !ok {
+			ar = &ActorRisk{Actor: actor, SeverityCounts: make(map[string]int)}
+			actors[actor] = ar
+		}
+		ar.GenomeCount++
+		ar.SeverityCounts[bucket]++
+		if genome.MaxCVSSScore > ar.MaxCVSSScore {
+			ar.MaxCVSSScore = genome.MaxCVSSScore
+		}
+		if genome.KEVExploited {
+			ar.KEVExploited++
+		}
+		if genome.LastSeen.After(ar.LastSeen) {
+			ar.LastSeen = genome.LastSeen
+		}
+
+		for _, platform := range genome.Platforms {
+			pr, ok := platforms[platform]
+			if !ok {
+				pr = &PlatformRisk{Platform: platform, SeverityCounts: make(map[string]int)}
+				platforms[platform] = pr
+			}
+			pr.GenomeCount++
+			pr.SeverityCounts[bucket]++
+			pr.ExposureScore += weight
+		}
+	}
+
+	for _, ar := range actors {
+		report.ByActor = append(report.ByActor, ar)
+	}
+	sort.Slice(report.ByActor, func(i, j int) bool {
+		return report.ByActor[i].Actor < report.ByActor[j].Actor
+	})
+
+	for _, pr := range platforms {
+		report.ByPlatform = append(report.ByPlatform, pr)
+	}
+	sort.Slice(report.ByPlatform, func(i, j int) bool {
+		return report.ByPlatform[i].Platform < report.ByPlatform[j].Platform
+	})
+
+	for _, pr := range report.ByPlatform {
+		report.TopExposedPlatforms = append(report.TopExposedPlatforms, PlatformExposure{
+			Platform:      pr.Platform,
+			ExposureScore: pr.ExposureScore,
+			GenomeCount:   pr.GenomeCount,
+		})
+	}
+	sort.Slice(report.TopExposedPlatforms, func(i, j int) bool {
+		return report.TopExposedPlatforms[i].ExposureScore > report.TopExposedPlatforms[j].ExposureScore
+	})
+	if len(report.TopExposedPlatforms) > topExposureLimit {
+		report.TopExposedPlatforms = report.TopExposedPlatforms[:topExposureLimit]
+	}
+
+	for _, ar := range report.ByActor {
+		if ar.LastSeen.IsZero() {
+			continue
+		}
+		report.RecentlyActiveActors = append(report.RecentlyActiveActors, ActorActivity{
+			Actor:       ar.Actor,
+			LastSeen:    ar.LastSeen,
+			GenomeCount: ar.GenomeCount,
+		})
+	}
+	sort.Slice(report.RecentlyActiveActors, func(i, j int) bool {
+		return report.RecentlyActiveActors[i].LastSeen.After(report.RecentlyActiveActors[j].LastSeen)
+	})
+	if len(report.RecentlyActiveActors) > topExposureLimit {
+		report.RecentlyActiveActors = report.RecentlyActiveActors[:topExposureLimit]
+	}
+
+	return report, nil
+}
+
+// RenderJSON marshals the report as indented JSON.
+func (r *RiskReport) RenderJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// RenderMarkdown renders the report as a Markdown document suitable for
+// pasting into a ticket or a chat channel.
+func (r *RiskReport) RenderMarkdown() ([]byte, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# ThreatDNA Risk Report\n\n")
+	fmt.Fprintf(&b, "Generated: %s\n\n", r.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Total genomes: %d\n\n", r.TotalGenomes)
+
+	fmt.Fprintf(&b, "## Severity totals\n\n")
+	fmt.Fprintf(&b, "| Severity | Count |\n|---|---|\n")
+	for _, bucket := range []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityUnscored} {
+		fmt.Fprintf(&b, "| %s | %d |\n", bucket, r.SeverityTotals[bucket])
+	}
+
+	fmt.Fprintf(&b, "\n## Top exposed platforms\n\n")
+	fmt.Fprintf(&b, "| Platform | Exposure score | Genomes |\n|---|---|---|\n")
+	for _, p := range r.TopExposedPlatforms {
+		fmt.Fprintf(&b, "| %s | %.1f | %d |\n", p.Platform, p.ExposureScore, p.GenomeCount)
+	}
+
+	fmt.Fprintf(&b, "\n## Recently active actors\n\n")
+	fmt.Fprintf(&b, "| Actor | Last seen | Genomes |\n|---|---|---|\n")
+	for _, a := range r.RecentlyActiveActors {
+		fmt.Fprintf(&b, "| %s | %s | %d |\n", a.Actor, a.LastSeen.Format("2006-01-02"), a.GenomeCount)
+	}
+
+	fmt.Fprintf(&b, "\n## By actor\n\n")
+	fmt.Fprintf(&b, "| Actor | Genomes | Critical | High | Medium | Low | Max CVSS | KEV |\n|---|---|---|---|---|---|---|---|\n")
+	for _, a := range r.ByActor {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %d | %.1f | %d |\n",
+			a.Actor, a.GenomeCount, a.SeverityCounts[SeverityCritical], a.SeverityCounts[SeverityHigh],
+			a.SeverityCounts[SeverityMedium], a.SeverityCounts[SeverityLow], a.MaxCVSSScore, a.KEVExploited)
+	}
+
+	return []byte(b.String()), nil
+}
+
+// riskReportHTMLTemplate is the small template RenderHTML executes. It's
+// kept inline rather than loaded from disk so the binary has no runtime
+// dependency on a templates directory.
+const riskReportHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>ThreatDNA Risk Report</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; }
+table { border-collapse: collapse; margin-bottom: 2rem; }
+th, td { border: 1px solid #ccc; padding: 0.3rem 0.6rem; text-align: left; }
+th { background: #f0f0f0; }
+</style>
+</head>
+<body>
+<h1>ThreatDNA Risk Report</h1>
+<p>Generated: {{.GeneratedAt.Format "2006-01-02T15:04:05Z07:00"}}</p>
+<p>Total genomes: {{.TotalGenomes}}</p>
+
+<h2>Severity totals</h2>
+<table>
+<tr><th>Severity</th><th>Count</th></tr>
+{{range $bucket, $count := .SeverityTotals}}<tr><td>{{$bucket}}</td><td>{{$count}}</td></tr>
+{{end}}</table>
+
+<h2>Top exposed platforms</h2>
+<table>
+<tr><th>Platform</th><th>Exposure score</th><th>Genomes</th></tr>
+{{range .TopExposedPlatforms}}<tr><td>{{.Platform}}</td><td>{{printf "%.1f" .ExposureScore}}</td><td>{{.GenomeCount}}</td></tr>
+{{end}}</table>
+
+<h2>Recently active actors</h2>
+<table>
+<tr><th>Actor</th><th>Last seen</th><th>Genomes</th></tr>
+{{range .RecentlyActiveActors}}<tr><td>{{.Actor}}</td><td>{{.LastSeen.Format "2006-01-02"}}</td><td>{{.GenomeCount}}</td></tr>
+{{end}}</table>
+
+<h2>By actor</h2>
+<table>
+<tr><th>Actor</th><th>Genomes</th><th>Max CVSS</th><th>KEV exploited</th></tr>
+{{range .ByActor}}<tr><td>{{.Actor}}</td><td>{{.GenomeCount}}</td><td>{{printf "%.1f" .MaxCVSSScore}}</td><td>{{.KEVExploited}}</td></tr>
+{{end}}</table>
+
+<h2>By platform</h2>
+<table>
+<tr><th>Platform</th><th>Genomes</th><th>Exposure score</th></tr>
+{{range .ByPlatform}}<tr><td>{{.Platform}}</td><td>{{.GenomeCount}}</td><td>{{printf "%.1f" .ExposureScore}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`
+
+// RenderHTML renders the report through riskReportHTMLTemplate.
+func (r *RiskReport) RenderHTML() ([]byte, error) {
+	tmpl, err := template.New("risk-report").Parse(riskReportHTMLTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse risk report template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, r); err != nil {
+		return nil, fmt.Errorf("failed to render risk report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,367 @@
+package threatdnacore
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleCategory names the kind of signal a Rule contributes to extraction.
+type RuleCategory string
+
+const (
+	CategoryActor     RuleCategory = "actor"
+	CategoryCampaign  RuleCategory = "campaign"
+	CategoryTTP       RuleCategory = "ttp"
+	CategoryIOCIP     RuleCategory = "ioc_ip"
+	CategoryIOCDomain RuleCategory = "ioc_domain"
+	CategoryIOCHash   RuleCategory = "ioc_hash"
+	CategoryIOCURL    RuleCategory = "ioc_url"
+	CategoryIOCEmail  RuleCategory = "ioc_email"
+	CategoryIOCSecret RuleCategory = "ioc_secret"
+	CategoryCVE       RuleCategory = "cve"
+	CategoryCustom    RuleCategory = "custom"
+)
+
+// Rule is a single named signature loaded from a rule file.
+type Rule struct {
+	ID                 string       `yaml:"id"`
+	Category           RuleCategory `yaml:"category"`
+	Patterns           []string     `yaml:"patterns"`
+	CaseSensitive      bool         `yaml:"case_sensitive"`
+	BlacklistedStrings []string     `yaml:"blacklisted_strings"`
+	BlacklistedPaths   []string     `yaml:"blacklisted_paths"`
+	Confidence         float64      `yaml:"confidence"`
+	Enabled            bool         `yaml:"enabled"`
+	// MinEntropy, when non-zero, requires a match's Shannon entropy (in
+	// bits/char) to be at least this value before it's accepted. Used by
+	// the generic high-entropy secret rule, where the pattern alone
+	// (a long base64/hex-looking run) is too permissive on its own.
+	MinEntropy float64 `yaml:"min_entropy"`
+
+	compiled []*regexp.Regexp
+}
+
+// ruleFile is the on-disk YAML shape: one file can define many rules.
+type ruleFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// RuleSet is the compiled, hot-reloadable collection of rules grouped by
+// category. It is safe for concurrent reads while Reload() runs.
+type RuleSet struct {
+	mu    sync.RWMutex
+	dir   string
+	rules map[RuleCategory][]*Rule
+
+	watchStop chan struct{}
+	modTimes  map[string]time.Time
+}
+
+// NewRuleSet creates an empty RuleSet rooted at dir. Call Reload (or
+// LoadFromDir) to populate it before use.
+func NewRuleSet(dir string) *RuleSet {
+	return &RuleSet{dir: dir, rules: make(map[RuleCategory][]*Rule)}
+}
+
+// LoadFromDir reads every rule file under dir and returns a compiled
+// RuleSet. If dir does not exist, a RuleSet seeded with the built-in
+// defaults is returned so callers keep working without a signature directory.
+func LoadFromDir(dir string) (*RuleSet, error) {
+	rs := NewRuleSet(dir)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		log.Printf("⚠️  Rules directory %s not found, using built-in defaults", dir)
+		rs.rules = defaultRules()
+		return rs, nil
+	}
+	if err := rs.Reload(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// Reload re-reads every *.yaml/*.yml file under rs.dir, replacing the active
+// rules atomically. Disabled rules and unreadable files are skipped with a
+// log line rather than aborting the whole load.
+func (rs *RuleSet) Reload() error {
+	entries, err := os.ReadDir(rs.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read rules directory %s: %w", rs.dir, err)
+	}
+
+	grouped := make(map[RuleCategory][]*Rule)
+	modTimes := make(map[string]time.Time)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+			continue
+		}
+
+		path := filepath.Join(rs.dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("⚠️  Skipping rule file %s: %v", name, err)
+			continue
+		}
+
+		var rf ruleFile
+		if err := yaml.Unmarshal(data, &rf); err != nil {
+			log.Printf("⚠️  Skipping rule file %s: %v", name, err)
+			continue
+		}
+
+		for i := range rf.Rules {
+			rule := &rf.Rules[i]
+			if !rule.Enabled {
+				continue
+			}
+			if err := rule.compile(); err != nil {
+				log.Printf("⚠️  Skipping rule %s in %s: %v", rule.ID, name, err)
+				continue
+			}
+			grouped[rule.Category] = append(grouped[rule.Category], rule)
+		}
+
+		if info, err := entry.Info(); err == nil {
+			modTimes[path] = info.ModTime()
+		}
+	}
+
+	rs.mu.Lock()
+	rs.rules = grouped
+	rs.modTimes = modTimes
+	rs.mu.Unlock()
+
+	log.Printf("✅ Loaded rule set from %s (%d categories)", rs.dir, len(grouped))
+	return nil
+}
+
+func (r *Rule) compile() error {
+	r.compiled = nil
+	prefix := "(?i)"
+	if r.CaseSensitive {
+		prefix = ""
+	}
+	for _, p := range r.Patterns {
+		compiled, err := regexp.Compile(prefix + p)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		r.compiled = append(r.compiled, compiled)
+	}
+	return nil
+}
+
+func (r *Rule) isBlacklisted(value string) bool {
+	lower := strings.ToLower(value)
+	for _, b := range r.BlacklistedStrings {
+		if strings.Contains(lower, strings.ToLower(b)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules returns the enabled, compiled rules for a category.
+func (rs *RuleSet) Rules(category RuleCategory) []*Rule {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.rules[category]
+}
+
+// MatchFirst returns the first non-blacklisted match for category in text,
+// preferring a capture group over the full match when the rule has one.
+func (rs *RuleSet) MatchFirst(category RuleCategory, text string) (string, *Rule) {
+	for _, rule := range rs.Rules(category) {
+		for _, pattern := range rule.compiled {
+			matches := pattern.FindStringSubmatch(text)
+			if len(matches) == 0 {
+				continue
+			}
+			value := matches[0]
+			if len(matches) > 1 && strings.TrimSpace(matches[1]) != "" {
+				value = matches[1]
+			}
+			value = strings.TrimSpace(value)
+			if value == "" || rule.isBlacklisted(value) {
+				continue
+			}
+			return value, rule
+		}
+	}
+	return "", nil
+}
+
+// CompiledPatterns returns every enabled rule's compiled regexes in
+// category, flattened in rule order. Use this (instead of MatchFirst) when a
+// caller needs every match in a category rather than just the first, e.g.
+// enumerating all IOC occurrences of a given type.
+func (rs *RuleSet) CompiledPatterns(category RuleCategory) []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, rule := range rs.Rules(category) {
+		patterns = append(patterns, rule.compiled...)
+	}
+	return patterns
+}
+
+// IsBlacklisted reports whether value matches a blacklisted_strings entry on
+// any enabled rule in category, the same check MatchFirst applies
+// internally, exposed for callers that enumerate matches via
+// CompiledPatterns instead.
+func (rs *RuleSet) IsBlacklisted(category RuleCategory, value string) bool {
+	for _, rule := range rs.Rules(category) {
+		if rule.isBlacklisted(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Watch polls rs.dir every interval and calls Reload when a rule file's
+// mtime changes, so analysts can drop in new signatures without a restart.
+// The returned stop function terminates the watcher goroutine.
+func (rs *RuleSet) Watch(interval time.Duration) (stop func()) {
+	rs.watchStop = make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-rs.watchStop:
+				return
+			case <-ticker.C:
+				if rs.changed() {
+					log.Printf("🔄 Detected rule file change under %s, reloading", rs.dir)
+					if err := rs.Reload(); err != nil {
+						log.Printf("⚠️  Failed to reload rule set: %v", err)
+					}
+				}
+			}
+		}
+	}()
+
+	return func() { close(rs.watchStop) }
+}
+
+func (rs *RuleSet) changed() bool {
+	entries, err := os.ReadDir(rs.dir)
+	if err != nil {
+		return false
+	}
+
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	if len(entries) != len(rs.modTimes) {
+		return true
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(rs.dir, entry.Name())
+		if known, ok := rs.modTimes[path]; !ok || !known.Equal(info.ModTime()) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultRules seeds a RuleSet with the patterns that used to be hard-coded
+// in HTMLParser and TechniqueExtractor, so extraction keeps working when no
+// signature directory is configured.
+func defaultRules() map[RuleCategory][]*Rule {
+	rules := map[RuleCategory][]*Rule{
+		CategoryActor: {
+			{ID: "known-actor-aliases", Category: CategoryActor, Confidence: 0.8, Patterns: []string{
+				`(APT\d+|Lazarus|Fancy Bear|Cozy Bear|Sandworm|Midnight Blizzard|Equation Group|Carbanak|FIN\d+|Turla|Silence|TA\d+|UNC\d+|OutSteel|SaintBot)`,
+			}},
+			{ID: "threat-actor-label", Category: CategoryActor, Confidence: 0.5, Patterns: []string{
+				`threat\s+actor[s]?:?\s*([A-Z][a-zA-Z0-9\s-]{3,30})`,
+				`group[s]?:?\s*([A-Z][a-zA-Z0-9\s-]{3,30})`,
+			}},
+		},
+		CategoryCampaign: {
+			{ID: "campaign-label", Category: CategoryCampaign, Confidence: 0.5, Patterns: []string{
+				`campaign[s]?:?\s*([A-Z][a-zA-Z0-9\s-]{3,50})`,
+				`operation[s]?:?\s*([A-Z][a-zA-Z0-9\s-]{3,50})`,
+			}},
+		},
+		CategoryIOCIP: {
+			{ID: "ipv4", Category: CategoryIOCIP, CaseSensitive: true, Confidence: 0.6, Patterns: []string{
+				`\b(?:[0-9]{1,3}\.){3}[0-9]{1,3}\b`,
+			}},
+		},
+		CategoryIOCDomain: {
+			{ID: "domain", Category: CategoryIOCDomain, CaseSensitive: true, Confidence: 0.5, Patterns: []string{
+				`\b[a-zA-Z0-9]([a-zA-Z0-9\-]{0,30}[a-zA-Z0-9])?(\.[a-zA-Z]{2,10})+\b`,
+			}, BlacklistedStrings: []string{"microsoft.com", "google.com", "github.com", "example.com"}},
+		},
+		CategoryIOCHash: {
+			{ID: "hex-hash", Category: CategoryIOCHash, CaseSensitive: true, Confidence: 0.6, Patterns: []string{
+				`\b[a-fA-F0-9]{32,64}\b`,
+			}},
+		},
+		CategoryIOCURL: {
+			{ID: "url", Category: CategoryIOCURL, CaseSensitive: true, Confidence: 0.5, Patterns: []string{
+				`\bhttps?://[^\s"'<>]+`,
+			}},
+		},
+		CategoryIOCEmail: {
+			{ID: "email", Category: CategoryIOCEmail, CaseSensitive: true, Confidence: 0.5, Patterns: []string{
+				`\b[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}\b`,
+			}},
+		},
+		CategoryCVE: {
+			{ID: "cve-id", Category: CategoryCVE, CaseSensitive: true, Confidence: 0.7, Patterns: []string{
+				`CVE-\d{4}-\d{4,7}`,
+			}},
+		},
+		CategoryIOCSecret: {
+			{ID: "aws-access-key", Category: CategoryIOCSecret, CaseSensitive: true, Confidence: 0.9, Patterns: []string{
+				`\bAKIA[0-9A-Z]{16}\b`,
+			}},
+			{ID: "gcp-service-account-key", Category: CategoryIOCSecret, CaseSensitive: true, Confidence: 0.9, Patterns: []string{
+				`"type":\s*"service_account"`,
+			}},
+			{ID: "github-token", Category: CategoryIOCSecret, CaseSensitive: true, Confidence: 0.9, Patterns: []string{
+				`\bgh[pousr]_[A-Za-z0-9]{36,255}\b`,
+			}},
+			{ID: "slack-token", Category: CategoryIOCSecret, CaseSensitive: true, Confidence: 0.9, Patterns: []string{
+				`\bxox[baprs]-[A-Za-z0-9-]{10,48}\b`,
+			}},
+			{ID: "jwt", Category: CategoryIOCSecret, CaseSensitive: true, Confidence: 0.7, Patterns: []string{
+				`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{5,}\b`,
+			}},
+			{ID: "pem-private-key", Category: CategoryIOCSecret, CaseSensitive: true, Confidence: 0.9, Patterns: []string{
+				`-----BEGIN [A-Z ]*PRIVATE KEY-----`,
+			}},
+			{ID: "generic-high-entropy", Category: CategoryIOCSecret, CaseSensitive: true, Confidence: 0.4, MinEntropy: 3.5, Patterns: []string{
+				`\b[A-Za-z0-9+/_-]{20,}\b`,
+			}},
+		},
+	}
+	for _, category := range rules {
+		for _, rule := range category {
+			rule.Enabled = true
+			_ = rule.compile()
+		}
+	}
+	return rules
+}
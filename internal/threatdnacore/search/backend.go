@@ -0,0 +1,42 @@
+// Package search abstracts the genome search index behind a Backend
+// interface so cmd/indexer and cmd/search can target either the embedded
+// Bleve index or a remote Elasticsearch/OpenSearch cluster without caring
+// which one is live. BleveBackend wraps threatdnacore.CreateBleveIndex's
+// existing mapping; ElasticBackend speaks the same SearchDocument shape
+// over the Elasticsearch/OpenSearch bulk and search REST APIs.
+package search
+
+import "threatdna/internal/threatdnacore"
+
+// Query is a backend-agnostic search request. Text is matched against
+// AllSourceText; Fields restricts which SearchDocument fields are
+// returned per hit (an empty slice returns none beyond ID/Score).
+type Query struct {
+	Text   string
+	Fields []string
+	Size   int
+}
+
+// Hit is a single backend-agnostic search result.
+type Hit struct {
+	ID     string
+	Score  float64
+	Fields map[string]interface{}
+}
+
+// Result is the backend-agnostic response to a Search call.
+type Result struct {
+	Hits  []Hit
+	Total uint64
+}
+
+// Backend is implemented by every search index this module can target.
+// CreateIndex opens the index at path, creating it if it does not already
+// exist. IndexBatch upserts docs keyed by genome ID. Search runs a query
+// and Close releases any held connections.
+type Backend interface {
+	CreateIndex(path string) error
+	IndexBatch(docs map[string]threatdnacore.SearchDocument) error
+	Search(query Query) (*Result, error)
+	Close() error
+}
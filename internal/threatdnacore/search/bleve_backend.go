@@ -0,0 +1,89 @@
+package search
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/blevesearch/bleve/v2"
+	"threatdna/internal/threatdnacore"
+)
+
+// BleveBackend is the default Backend, backed by a single on-disk Bleve
+// index. It is the only backend that makes sense for a single-node
+// deployment; ElasticBackend exists for when one Bleve directory no
+// longer scales.
+type BleveBackend struct {
+	index bleve.Index
+}
+
+// NewBleveBackend returns an unopened BleveBackend. Call CreateIndex
+// before IndexBatch or Search.
+func NewBleveBackend() *BleveBackend {
+	return &BleveBackend{}
+}
+
+// CreateIndex opens the Bleve index at path, creating it with
+// threatdnacore.CreateBleveIndex's mapping if it does not already exist.
+func (b *BleveBackend) CreateIndex(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		index, err := bleve.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open existing Bleve index at %s: %w", path, err)
+		}
+		b.index = index
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to stat Bleve index path %s: %w", path, err)
+	}
+
+	b.index = threatdnacore.CreateBleveIndex(path)
+	return nil
+}
+
+// Index returns the underlying Bleve index, for callers that need
+// lower-level access CreateIndex/IndexBatch don't expose (e.g.
+// threatdnacore.IndexBleveData's batched, progress-reporting indexing).
+func (b *BleveBackend) Index() bleve.Index {
+	return b.index
+}
+
+// IndexBatch upserts docs into the index as a single Bleve batch.
+func (b *BleveBackend) IndexBatch(docs map[string]threatdnacore.SearchDocument) error {
+	batch := b.index.NewBatch()
+	for id, doc := range docs {
+		if err := batch.Index(id, doc); err != nil {
+			return fmt.Errorf("failed to add %s to batch: %w", id, err)
+		}
+	}
+	return b.index.Batch(batch)
+}
+
+// Search runs query.Text as a Bleve match query against all_source_text.
+func (b *BleveBackend) Search(query Query) (*Result, error) {
+	matchQuery := bleve.NewMatchQuery(query.Text)
+	matchQuery.SetField("all_source_text")
+	req := bleve.NewSearchRequest(matchQuery)
+	req.Fields = query.Fields
+	if query.Size > 0 {
+		req.Size = query.Size
+	}
+
+	res, err := b.index.Search(req)
+	if err != nil {
+		return nil, fmt.Errorf("bleve search failed: %w", err)
+	}
+
+	hits := make([]Hit, 0, len(res.Hits))
+	for _, h := range res.Hits {
+		hits = append(hits, Hit{ID: h.ID, Score: h.Score, Fields: h.Fields})
+	}
+	return &Result{Hits: hits, Total: res.Total}, nil
+}
+
+// Close closes the underlying Bleve index.
+func (b *BleveBackend) Close() error {
+	if b.index == nil {
+		return nil
+	}
+	return b.index.Close()
+}
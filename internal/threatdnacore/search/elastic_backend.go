@@ -0,0 +1,315 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"threatdna/internal/threatdnacore"
+)
+
+// defaultElasticBatchSize and defaultElasticFlushInterval size the bulk
+// pipeline when ElasticBackend's caller leaves them at zero.
+const (
+	defaultElasticBatchSize     = 500
+	defaultElasticFlushInterval = 5 * time.Second
+	elasticBulkMaxAttempts      = 5
+	elasticBulkInitialBackoff   = 500 * time.Millisecond
+)
+
+// ElasticBackend is a Backend implementation that indexes and searches
+// SearchDocuments against an Elasticsearch or OpenSearch cluster, for
+// deployments where genome volume has outgrown a single Bleve directory.
+// Documents queue up and flush as a bulk request either once batchSize is
+// reached or every flushInterval, whichever comes first.
+type ElasticBackend struct {
+	url           string
+	indexName     string
+	httpClient    *http.Client
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending []bulkItem
+
+	stopCh    chan struct{}
+	flushDone chan struct{}
+}
+
+type bulkItem struct {
+	id  string
+	doc threatdnacore.SearchDocument
+}
+
+// NewElasticBackend returns an ElasticBackend targeting url (e.g.
+// "http://localhost:9200"). batchSize and flushInterval of 0 fall back to
+// defaultElasticBatchSize/defaultElasticFlushInterval.
+func NewElasticBackend(url string, batchSize int, flushInterval time.Duration) *ElasticBackend {
+	if batchSize <= 0 {
+		batchSize = defaultElasticBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultElasticFlushInterval
+	}
+	return &ElasticBackend{
+		url:           strings.TrimSuffix(url, "/"),
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+	}
+}
+
+// elasticMapping mirrors threatdnacore.CreateBleveIndex's field mapping:
+// actor/campaign/ttps/tactics/platforms/cve_id/detections as keyword,
+// all_source_text as analyzed text, cvss_score numeric, kev_exploited
+// boolean, and first_seen/last_seen as date.
+const elasticMapping = `{
+  "mappings": {
+    "properties": {
+      "actor":           {"type": "keyword"},
+      "campaign":        {"type": "keyword"},
+      "ttps":            {"type": "keyword"},
+      "tactics":         {"type": "keyword"},
+      "platforms":       {"type": "keyword"},
+      "cve_id":          {"type": "keyword"},
+      "detections":      {"type": "keyword"},
+      "all_source_text": {"type": "text", "analyzer": "standard"},
+      "confidence":      {"type": "double"},
+      "cvss_score":      {"type": "double"},
+      "kev_exploited":   {"type": "boolean"},
+      "first_seen":      {"type": "date"},
+      "last_seen":       {"type": "date"},
+      "type":            {"type": "keyword"}
+    }
+  }
+}`
+
+// CreateIndex creates the Elasticsearch/OpenSearch index named path (an
+// already-existing index is not an error) and starts the background
+// flush loop that honors flushInterval.
+func (e *ElasticBackend) CreateIndex(path string) error {
+	e.indexName = path
+
+	req, err := http.NewRequest(http.MethodPut, e.url+"/"+path, strings.NewReader(elasticMapping))
+	if err != nil {
+		return fmt.Errorf("failed to build create-index request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to create index %s at %s: %w", path, e.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("failed to create index %s: unexpected status %d", path, resp.StatusCode)
+	}
+	// StatusBadRequest with resource_already_exists_exception is treated as
+	// success, since CreateIndex is also called against a live index.
+
+	e.stopCh = make(chan struct{})
+	e.flushDone = make(chan struct{})
+	go e.flushLoop()
+	return nil
+}
+
+// flushLoop flushes pending documents every flushInterval until Close.
+func (e *ElasticBackend) flushLoop() {
+	defer close(e.flushDone)
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.flush(); err != nil {
+				log.Printf("⚠️  Periodic Elasticsearch flush failed: %v", err)
+			}
+		case <-e.stopCh:
+			return
+		}
+	}
+}
+
+// IndexBatch queues docs and flushes immediately once batchSize is
+// reached; otherwise the next periodic flush (or a later IndexBatch call)
+// picks them up.
+func (e *ElasticBackend) IndexBatch(docs map[string]threatdnacore.SearchDocument) error {
+	e.mu.Lock()
+	for id, doc := range docs {
+		e.pending = append(e.pending, bulkItem{id: id, doc: doc})
+	}
+	shouldFlush := len(e.pending) >= e.batchSize
+	e.mu.Unlock()
+
+	if shouldFlush {
+		return e.flush()
+	}
+	return nil
+}
+
+// flush drains the pending queue and sends it to Elasticsearch in chunks
+// of at most batchSize documents per bulk request.
+func (e *ElasticBackend) flush() error {
+	e.mu.Lock()
+	if len(e.pending) == 0 {
+		e.mu.Unlock()
+		return nil
+	}
+	items := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	for start := 0; start < len(items); start += e.batchSize {
+		end := start + e.batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		if err := e.sendBulkWithRetry(items[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendBulkWithRetry POSTs a single bulk request for items, retrying with
+// exponential backoff when Elasticsearch responds 429 (too many requests),
+// mirroring GenomeBuilder.processMessage's Kafka retry loop.
+func (e *ElasticBackend) sendBulkWithRetry(items []bulkItem) error {
+	body := e.buildBulkBody(items)
+
+	backoff := elasticBulkInitialBackoff
+	var lastErr error
+	for attempt := 1; attempt <= elasticBulkMaxAttempts; attempt++ {
+		req, err := http.NewRequest(http.MethodPost, e.url+"/"+e.indexName+"/_bulk", bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to build bulk request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+
+		resp, err := e.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusTooManyRequests {
+				lastErr = fmt.Errorf("bulk request rate limited (status 429)")
+			} else if resp.StatusCode >= 300 {
+				return fmt.Errorf("bulk request failed: status %d", resp.StatusCode)
+			} else {
+				return nil
+			}
+		}
+
+		log.Printf("Transient error sending bulk request of %d docs (attempt %d/%d): %v. Retrying in %v...",
+			len(items), attempt, elasticBulkMaxAttempts, lastErr, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return fmt.Errorf("giving up on bulk request after %d attempts: %w", elasticBulkMaxAttempts, lastErr)
+}
+
+// buildBulkBody renders items as newline-delimited index actions per the
+// Elasticsearch/OpenSearch _bulk API.
+func (e *ElasticBackend) buildBulkBody(items []bulkItem) []byte {
+	var buf bytes.Buffer
+	for _, item := range items {
+		action := map[string]interface{}{
+			"index": map[string]string{"_index": e.indexName, "_id": item.id},
+		}
+		actionLine, _ := json.Marshal(action)
+		docLine, _ := json.Marshal(item.doc)
+		buf.Write(actionLine)
+		buf.WriteByte('\n')
+		buf.Write(docLine)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// elasticSearchRequest is the subset of the Elasticsearch/OpenSearch query
+// DSL Search translates a Query into.
+type elasticSearchRequest struct {
+	Query  map[string]interface{} `json:"query"`
+	Size   int                    `json:"size,omitempty"`
+	Source []string               `json:"_source,omitempty"`
+}
+
+type elasticSearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID     string                 `json:"_id"`
+			Score  float64                `json:"_score"`
+			Source map[string]interface{} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Search translates query into an Elasticsearch match query against
+// all_source_text and runs it.
+func (e *ElasticBackend) Search(query Query) (*Result, error) {
+	reqBody := elasticSearchRequest{
+		Query: map[string]interface{}{
+			"match": map[string]interface{}{"all_source_text": query.Text},
+		},
+		Source: query.Fields,
+	}
+	if query.Size > 0 {
+		reqBody.Size = query.Size
+	}
+
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.url+"/"+e.indexName+"/_search", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("elasticsearch search failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("elasticsearch search failed: status %d", resp.StatusCode)
+	}
+
+	var parsed elasticSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	hits := make([]Hit, 0, len(parsed.Hits.Hits))
+	for _, h := range parsed.Hits.Hits {
+		// An empty _source list is omitted from the request, so the cluster
+		// returns the whole document; drop it to match Query.Fields.
+		fields := h.Source
+		if len(query.Fields) == 0 {
+			fields = nil
+		}
+		hits = append(hits, Hit{ID: h.ID, Score: h.Score, Fields: fields})
+	}
+	return &Result{Hits: hits, Total: uint64(parsed.Hits.Total.Value)}, nil
+}
+
+// Close stops the flush loop and sends any remaining queued documents.
+func (e *ElasticBackend) Close() error {
+	if e.stopCh != nil {
+		close(e.stopCh)
+		<-e.flushDone
+	}
+	return e.flush()
+}
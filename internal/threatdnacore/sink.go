@@ -0,0 +1,159 @@
+package threatdnacore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RecordSink is any fan-out destination the producer can publish ingested
+// CTIRecords to. It exists so operators who don't want to run Kafka can
+// still consume ThreatDNA's output — a producer selects one or more sinks
+// (see cmd/producer's SINKS env var) instead of being hard-wired to Kafka.
+// The Kafka sink itself lives in cmd/producer, where the kafkaio.Producer/
+// DLQPublisher/BatchPublisher it wraps are already assembled.
+type RecordSink interface {
+	// Publish delivers records to the sink. Implementations should report
+	// a partial failure (some records published, some not) as an error
+	// rather than silently dropping the rest.
+	Publish(ctx context.Context, records []CTIRecord) error
+	// Close releases any held connections.
+	Close() error
+}
+
+// defaultElasticSinkTimeout bounds how long ElasticSink waits for a bulk
+// request before giving up.
+const defaultElasticSinkTimeout = 30 * time.Second
+
+// ElasticSink is a RecordSink that bulk-indexes CTIRecords directly into
+// Elasticsearch/OpenSearch, the same raw net/http _bulk approach
+// search.ElasticBackend uses rather than pulling in the official client
+// for one index call per batch. Indices rotate daily (index-YYYY.MM.DD)
+// and are exposed under a configurable alias so downstream consumers
+// query the alias instead of tracking the rotation themselves.
+type ElasticSink struct {
+	url        string
+	indexBase  string
+	alias      string
+	httpClient *http.Client
+}
+
+// NewElasticSink returns an ElasticSink targeting url (e.g.
+// "http://localhost:9200"), writing to daily indices named
+// "<indexBase>-YYYY.MM.DD" and aliasing them under alias. alias may be
+// empty to skip alias management.
+func NewElasticSink(url, indexBase, alias string) *ElasticSink {
+	return &ElasticSink{
+		url:        strings.TrimSuffix(url, "/"),
+		indexBase:  indexBase,
+		alias:      alias,
+		httpClient: &http.Client{Timeout: defaultElasticSinkTimeout},
+	}
+}
+
+func (s *ElasticSink) Publish(ctx context.Context, records []CTIRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	index := s.indexName(time.Now())
+	if err := s.ensureIndex(ctx, index); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		action := map[string]interface{}{
+			"index": map[string]string{"_index": index, "_id": record.ID},
+		}
+		actionLine, _ := json.Marshal(action)
+		docLine, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal record %s for elastic sink: %w", record.ID, err)
+		}
+		buf.Write(actionLine)
+		buf.WriteByte('\n')
+		buf.Write(docLine)
+		buf.WriteByte('\n')
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+"/_bulk", &buf)
+	if err != nil {
+		return fmt.Errorf("failed to build bulk request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("elastic sink bulk request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("elastic sink bulk request failed: status %d", resp.StatusCode)
+	}
+	var bulkResp struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&bulkResp); err != nil {
+		return fmt.Errorf("failed to decode elastic sink bulk response: %w", err)
+	}
+	if bulkResp.Errors {
+		return fmt.Errorf("elastic sink bulk request: some of %d records were not indexed", len(records))
+	}
+	return nil
+}
+
+// ensureIndex creates index (tolerating one that already exists) and, if
+// an alias was configured, points it at index so queries against the
+// alias see every rotated index written to so far.
+func (s *ElasticSink) ensureIndex(ctx context.Context, index string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.url+"/"+index, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build create-index request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to create index %s: %w", index, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("failed to create index %s: unexpected status %d", index, resp.StatusCode)
+	}
+
+	if s.alias == "" {
+		return nil
+	}
+	aliasBody, _ := json.Marshal(map[string]interface{}{
+		"actions": []map[string]interface{}{
+			{"add": map[string]string{"index": index, "alias": s.alias}},
+		},
+	})
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, s.url+"/_aliases", bytes.NewReader(aliasBody))
+	if err != nil {
+		return fmt.Errorf("failed to build alias request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to point alias %s at index %s: %w", s.alias, index, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to point alias %s at index %s: status %d", s.alias, index, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *ElasticSink) indexName(t time.Time) string {
+	return fmt.Sprintf("%s-%s", s.indexBase, t.Format("2006.01.02"))
+}
+
+// Close is a no-op; ElasticSink holds no long-lived connection beyond the
+// shared http.Client.
+func (s *ElasticSink) Close() error {
+	return nil
+}
@@ -0,0 +1,42 @@
+package threatdnacore
+
+import (
+	"context"
+	"os"
+)
+
+// SourceParser lets DataIngester dispatch an ingested file to whichever
+// registered parser recognizes its format, instead of switching on file
+// extension inline. DataIngester tries each registered parser's CanHandle
+// in registration order and uses the first match, so a more specific
+// parser should be registered before a catch-all one.
+type SourceParser interface {
+	// CanHandle reports whether this parser can ingest the file at path,
+	// given its path and the leading bytes of its content. header may be
+	// shorter than readFileHeaderSize for small files, and nil if the
+	// file could not be read.
+	CanHandle(path string, header []byte) bool
+	// Parse extracts zero or more CTIRecords from the file at path.
+	Parse(ctx context.Context, path string) ([]CTIRecord, error)
+}
+
+// readFileHeaderSize is how many leading bytes of a file CanHandle
+// implementations get to sniff for a format's magic bytes.
+const readFileHeaderSize = 512
+
+// readFileHeader reads up to readFileHeaderSize bytes from path for a
+// SourceParser's CanHandle to sniff. A read error yields a nil header
+// rather than failing ingestion; CanHandle implementations should treat a
+// nil/short header as "no magic-byte match" and fall back to the
+// extension check.
+func readFileHeader(path string) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	buf := make([]byte, readFileHeaderSize)
+	n, _ := f.Read(buf)
+	return buf[:n]
+}
@@ -0,0 +1,228 @@
+package threatdnacore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// genomeSTIXObject covers every SDO/SRO type ExportSTIX emits for a genome:
+// campaign/intrusion-set, attack-pattern, indicator, vulnerability, and the
+// relationships linking them, in one struct since a bundle mixes object
+// types.
+type genomeSTIXObject struct {
+	Type               string              `json:"type"`
+	SpecVersion        string              `json:"spec_version"`
+	ID                 string              `json:"id"`
+	Created            string              `json:"created"`
+	Modified           string              `json:"modified"`
+	Name               string              `json:"name,omitempty"`
+	Pattern            string              `json:"pattern,omitempty"`
+	PatternType        string              `json:"pattern_type,omitempty"`
+	ValidFrom          string              `json:"valid_from,omitempty"`
+	RelationshipType   string              `json:"relationship_type,omitempty"`
+	SourceRef          string              `json:"source_ref,omitempty"`
+	TargetRef          string              `json:"target_ref,omitempty"`
+	ExternalReferences []ExternalReference `json:"external_references,omitempty"`
+}
+
+type genomeSTIXBundle struct {
+	Type    string             `json:"type"`
+	ID      string             `json:"id"`
+	Objects []genomeSTIXObject `json:"objects"`
+}
+
+// ExportSTIX writes genomes to w as a single STIX 2.1 bundle: each genome
+// becomes a campaign SDO (if it has a Campaign name) or an intrusion-set SDO
+// otherwise, one attack-pattern per distinct technique ID with a "uses"
+// relationship, one indicator per IOC carried in genome.Metadata["iocs"]
+// with an "indicates" relationship, and one vulnerability per CVE with a
+// "targets" relationship. This is the interop surface OpenCTI, MISP, and
+// TAXII 2.1 servers expect; ImportSTIX is its inverse.
+func (gb *GenomeBuilder) ExportSTIX(w io.Writer, genomes []*Genome) error {
+	bundle := genomeSTIXBundle{
+		Type: "bundle",
+		ID:   generateSTIXID("bundle", fmt.Sprintf("genome-export:%d", time.Now().UnixNano())),
+	}
+
+	attackPatternIDs := make(map[string]string)
+	vulnIDs := make(map[string]string)
+
+	for _, genome := range genomes {
+		subjectType := "intrusion-set"
+		subjectName := genome.Actor
+		if genome.Campaign != "" {
+			subjectType = "campaign"
+			subjectName = genome.Campaign
+		}
+		if subjectName == "" {
+			subjectName = genome.ID
+		}
+
+		created := stixTimestampFor(genome.FirstSeen)
+		modified := stixTimestampFor(genome.LastSeen)
+		subjectID := generateSTIXID(subjectType, subjectName)
+		bundle.Objects = append(bundle.Objects, genomeSTIXObject{
+			Type:        subjectType,
+			SpecVersion: "2.1",
+			ID:          subjectID,
+			Created:     created,
+			Modified:    modified,
+			Name:        subjectName,
+		})
+
+		for _, techniqueID := range genome.TTPs {
+			attackPatternID, ok := attackPatternIDs[techniqueID]
+			if !ok {
+				attackPatternID = generateSTIXID("attack-pattern", techniqueID)
+				attackPatternIDs[techniqueID] = attackPatternID
+				bundle.Objects = append(bundle.Objects, genomeSTIXObject{
+					Type:        "attack-pattern",
+					SpecVersion: "2.1",
+					ID:          attackPatternID,
+					Created:     created,
+					Modified:    created,
+					Name:        techniqueID,
+					ExternalReferences: []ExternalReference{
+						{SourceName: "mitre-attack", ExternalID: techniqueID},
+					},
+				})
+			}
+			bundle.Objects = append(bundle.Objects, genomeSTIXObject{
+				Type:             "relationship",
+				SpecVersion:      "2.1",
+				ID:               generateSTIXID("relationship", fmt.Sprintf("%s:uses:%s", subjectID, attackPatternID)),
+				Created:          created,
+				Modified:         created,
+				RelationshipType: "uses",
+				SourceRef:        subjectID,
+				TargetRef:        attackPatternID,
+			})
+		}
+
+		for _, ioc := range genomeIOCs(genome) {
+			indicatorID := generateSTIXID("indicator", fmt.Sprintf("%s:%s", ioc.Type, ioc.Value))
+			bundle.Objects = append(bundle.Objects, genomeSTIXObject{
+				Type:        "indicator",
+				SpecVersion: "2.1",
+				ID:          indicatorID,
+				Created:     created,
+				Modified:    created,
+				Pattern:     stixPatternForIOC(ioc),
+				PatternType: "stix",
+				ValidFrom:   created,
+			})
+			bundle.Objects = append(bundle.Objects, genomeSTIXObject{
+				Type:             "relationship",
+				SpecVersion:      "2.1",
+				ID:               generateSTIXID("relationship", fmt.Sprintf("%s:indicates:%s", indicatorID, subjectID)),
+				Created:          created,
+				Modified:         created,
+				RelationshipType: "indicates",
+				SourceRef:        indicatorID,
+				TargetRef:        subjectID,
+			})
+		}
+
+		for _, cveID := range genome.CVEs {
+			vulnID, ok := vulnIDs[cveID]
+			if !ok {
+				vulnID = generateSTIXID("vulnerability", cveID)
+				vulnIDs[cveID] = vulnID
+				bundle.Objects = append(bundle.Objects, genomeSTIXObject{
+					Type:        "vulnerability",
+					SpecVersion: "2.1",
+					ID:          vulnID,
+					Created:     created,
+					Modified:    created,
+					Name:        cveID,
+					ExternalReferences: []ExternalReference{
+						{SourceName: "cve", ExternalID: cveID},
+					},
+				})
+			}
+			bundle.Objects = append(bundle.Objects, genomeSTIXObject{
+				Type:             "relationship",
+				SpecVersion:      "2.1",
+				ID:               generateSTIXID("relationship", fmt.Sprintf("%s:targets:%s", subjectID, vulnID)),
+				Created:          created,
+				Modified:         created,
+				RelationshipType: "targets",
+				SourceRef:        subjectID,
+				TargetRef:        vulnID,
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(bundle); err != nil {
+		return fmt.Errorf("failed to encode STIX bundle: %w", err)
+	}
+	return nil
+}
+
+// genomeIOCs recovers the IOCs BuildGenome rolled up into
+// genome.Metadata["iocs"] — the only place individual indicator values
+// survive aggregation, since Genome itself only tracks IOCCount.
+func genomeIOCs(genome *Genome) []IOC {
+	iocs, _ := genome.Metadata["iocs"].([]IOC)
+	return iocs
+}
+
+// stixPatternForIOC renders ioc as a STIX pattern expression, the inverse of
+// iocFromSTIXPattern. Unrecognized IOC types fall back to a custom
+// x-threatdna object rather than being dropped.
+func stixPatternForIOC(ioc IOC) string {
+	switch ioc.Type {
+	case "ip":
+		return fmt.Sprintf("[ipv4-addr:value = '%s']", ioc.Value)
+	case "domain":
+		return fmt.Sprintf("[domain-name:value = '%s']", ioc.Value)
+	case "url":
+		return fmt.Sprintf("[url:value = '%s']", ioc.Value)
+	case "email":
+		return fmt.Sprintf("[email-addr:value = '%s']", ioc.Value)
+	case "hash":
+		return fmt.Sprintf("[file:hashes.'%s' = '%s']", hashAlgoForValue(ioc.Value), ioc.Value)
+	default:
+		return fmt.Sprintf("[x-threatdna:value = '%s']", ioc.Value)
+	}
+}
+
+// hashAlgoForValue maps a hash IOC's hex length to the STIX hashes
+// vocabulary key, mirroring ioc_normalizer.go's validHash length switch.
+func hashAlgoForValue(value string) string {
+	switch len(value) {
+	case 32:
+		return "MD5"
+	case 40:
+		return "SHA-1"
+	default:
+		return "SHA-256"
+	}
+}
+
+// ImportSTIX reads a STIX 2.1 bundle from r and builds (and indexes)
+// genomes from it exactly as a batch of Kafka CTIRecords would, so
+// ThreatDNA can ingest feeds from OpenCTI, MISP, or any TAXII 2.1 server
+// without a bespoke connector. It is the inverse of ExportSTIX.
+func (gb *GenomeBuilder) ImportSTIX(r io.Reader) ([]*Genome, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read STIX bundle: %w", err)
+	}
+
+	records, err := NewSTIXBundleParser().ParseBundle(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse STIX bundle: %w", err)
+	}
+
+	genomes, err := gb.BuildGenomesFromRecords(records, NoopProgress{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build genomes from STIX bundle: %w", err)
+	}
+	return genomes, nil
+}
@@ -0,0 +1,248 @@
+package threatdnacore
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// STIXBundleParser converts STIX 2.1 SDOs/SROs directly into CTIRecords,
+// instead of running the free-text keyword extractors HTMLParser relies on.
+// Object relationships the bundle already asserts (an intrusion-set "uses"
+// an attack-pattern, an indicator "indicates" a campaign, ...) become
+// TTPs/IOCs/tags at full confidence, since the source report already did
+// the analysis HTMLParser has to heuristically reconstruct from prose.
+type STIXBundleParser struct{}
+
+// NewSTIXBundleParser creates a STIXBundleParser. It holds no state, so
+// a single instance can be reused across bundles.
+func NewSTIXBundleParser() *STIXBundleParser {
+	return &STIXBundleParser{}
+}
+
+// stixObject is the subset of STIX 2.1 SDO/SRO fields ParseBundle reads.
+// A single struct covers every object type this parser recognizes, since
+// STIX bundles are naturally object-heterogeneous and Go lacks ad-hoc
+// sum types.
+type stixObject struct {
+	Type               string               `json:"type"`
+	ID                 string               `json:"id"`
+	Created            string               `json:"created"`
+	Modified           string               `json:"modified"`
+	Name               string               `json:"name"`
+	Pattern            string               `json:"pattern"`
+	ExternalReferences []ExternalReference  `json:"external_references"`
+	KillChainPhases    []KillChainPhase     `json:"kill_chain_phases"`
+	RelationshipType   string               `json:"relationship_type"`
+	SourceRef          string               `json:"source_ref"`
+	TargetRef          string               `json:"target_ref"`
+}
+
+// stixBundleEnvelope mirrors the top-level "objects" array every STIX 2.1
+// bundle is wrapped in.
+type stixBundleEnvelope struct {
+	Objects []stixObject `json:"objects"`
+}
+
+// ParseBundle converts every indicator, malware, intrusion-set, campaign,
+// attack-pattern, and relationship SDO/SRO in data into CTIRecords. Each
+// intrusion-set or campaign in the bundle becomes one CTIRecord; its TTPs,
+// IOCs, and malware tags are whatever relationships connect it to an
+// attack-pattern, indicator, or malware object. A bundle with no intrusion-set or campaign (e.g. a bare
+// indicator feed) still produces a single unattributed CTIRecord so its
+// IOCs aren't silently dropped.
+func (p *STIXBundleParser) ParseBundle(data []byte) ([]CTIRecord, error) {
+	var envelope stixBundleEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal STIX bundle: %w", err)
+	}
+
+	techniqueIDByRef := make(map[string]string) // attack-pattern STIX ID -> "T1059"
+	tacticByRef := make(map[string]string)      // attack-pattern STIX ID -> tactic
+	nameByRef := make(map[string]string)        // intrusion-set/campaign/malware STIX ID -> name
+	malwareNameByRef := make(map[string]string) // malware STIX ID -> name
+	iocByRef := make(map[string]IOC)            // indicator STIX ID -> IOC
+	timeByRef := make(map[string]string)        // any SDO STIX ID -> created timestamp
+	var subjectRefs []string                    // intrusion-set/campaign STIX IDs, in bundle order
+	var relationships []stixObject
+
+	for _, obj := range envelope.Objects {
+		timeByRef[obj.ID] = obj.Created
+
+		switch obj.Type {
+		case "attack-pattern":
+			for _, ref := range obj.ExternalReferences {
+				if ref.SourceName == "mitre-attack" {
+					techniqueIDByRef[obj.ID] = ref.ExternalID
+					break
+				}
+			}
+			if len(obj.KillChainPhases) > 0 {
+				tacticByRef[obj.ID] = obj.KillChainPhases[0].PhaseName
+			}
+		case "intrusion-set", "campaign", "malware":
+			nameByRef[obj.ID] = obj.Name
+			if obj.Type != "malware" {
+				subjectRefs = append(subjectRefs, obj.ID)
+			} else {
+				malwareNameByRef[obj.ID] = obj.Name
+			}
+		case "indicator":
+			if ioc, ok := iocFromSTIXPattern(obj.Pattern); ok {
+				iocByRef[obj.ID] = ioc
+			}
+		case "relationship":
+			relationships = append(relationships, obj)
+		}
+	}
+
+	if len(subjectRefs) == 0 {
+		// No intrusion-set/campaign to attribute to; still surface every
+		// attack-pattern/indicator the bundle carried, unattributed, instead
+		// of dropping them for lack of a relationship to hang them off.
+		return []CTIRecord{unattributedRecord(techniqueIDByRef, tacticByRef, iocByRef)}, nil
+	}
+
+	records := make([]CTIRecord, 0, len(subjectRefs))
+	for _, ref := range subjectRefs {
+		records = append(records, p.recordForSubject(ref, nameByRef[ref], malwareNameByRef, techniqueIDByRef, tacticByRef, iocByRef, relationships, timeByRef[ref]))
+	}
+	return records, nil
+}
+
+// unattributedRecord builds a single CTIRecord out of every attack-pattern
+// and indicator in a bundle that had no intrusion-set/campaign to
+// attribute them to.
+func unattributedRecord(techniqueIDByRef, tacticByRef map[string]string, iocByRef map[string]IOC) CTIRecord {
+	record := CTIRecord{
+		ID:     generateSTIXRecordID(""),
+		Source: "stix-bundle",
+		Date:   time.Now(),
+		Tags:   []string{"stix-native"},
+	}
+	for ref, techniqueID := range techniqueIDByRef {
+		record.TTPs = append(record.TTPs, TTP{
+			TechniqueID: techniqueID,
+			Confidence:  1.0,
+			Context:     "stix-attack-pattern",
+			Tactic:      tacticByRef[ref],
+		})
+	}
+	for _, ioc := range iocByRef {
+		record.IOCs = append(record.IOCs, ioc)
+	}
+	return record
+}
+
+// recordForSubject builds the CTIRecord for one intrusion-set/campaign: it
+// walks every relationship touching subjectRef and resolves the other end
+// against an attack-pattern, indicator, or malware object, each at full
+// confidence since the bundle asserted the link directly.
+func (p *STIXBundleParser) recordForSubject(subjectRef, name string, nameByRef, techniqueIDByRef, tacticByRef map[string]string, iocByRef map[string]IOC, relationships []stixObject, created string) CTIRecord {
+	record := CTIRecord{
+		ID:     generateSTIXRecordID(subjectRef),
+		Source: "stix-bundle",
+		Date:   parseSTIXTimestamp(created),
+		Actor:  name,
+		Tags:   []string{"stix-native"},
+	}
+
+	seenTTP := make(map[string]bool)
+	seenIOC := make(map[string]bool)
+
+	for _, rel := range relationships {
+		var other string
+		switch subjectRef {
+		case rel.SourceRef:
+			other = rel.TargetRef
+		case rel.TargetRef:
+			other = rel.SourceRef
+		default:
+			continue
+		}
+
+		if techniqueID, ok := techniqueIDByRef[other]; ok && !seenTTP[techniqueID] {
+			seenTTP[techniqueID] = true
+			record.TTPs = append(record.TTPs, TTP{
+				TechniqueID: techniqueID,
+				Confidence:  1.0,
+				Context:     "stix-relationship:" + rel.RelationshipType,
+				Tactic:      tacticByRef[other],
+			})
+			continue
+		}
+		if ioc, ok := iocByRef[other]; ok && !seenIOC[ioc.Value] {
+			seenIOC[ioc.Value] = true
+			record.IOCs = append(record.IOCs, ioc)
+			continue
+		}
+		if malwareName, ok := nameByRef[other]; ok && malwareName != "" && malwareName != name {
+			record.Tags = append(record.Tags, "malware:"+malwareName)
+		}
+	}
+
+	return record
+}
+
+// stixIndicatorPattern extracts the object path and literal value out of a
+// single-comparison STIX pattern, e.g. "[ipv4-addr:value = '1.2.3.4']".
+// ParseBundle only needs the common single-indicator case; multi-comparison
+// and multi-observation patterns are left unrecognized rather than
+// misparsed.
+var stixIndicatorPattern = regexp.MustCompile(`^\[([a-zA-Z0-9_-]+):([a-zA-Z0-9_.'-]+)\s*=\s*'([^']*)'\]$`)
+
+// iocFromSTIXPattern is the inverse of stix_genome.go's stixPatternForIOC: it
+// recovers an IOC's type/value from the STIX pattern expression an
+// indicator SDO carries.
+func iocFromSTIXPattern(pattern string) (IOC, bool) {
+	m := stixIndicatorPattern.FindStringSubmatch(pattern)
+	if m == nil {
+		return IOC{}, false
+	}
+	object, path, value := m[1], m[2], m[3]
+
+	var iocType string
+	switch {
+	case object == "ipv4-addr" && path == "value":
+		iocType = "ip"
+	case object == "domain-name" && path == "value":
+		iocType = "domain"
+	case object == "url" && path == "value":
+		iocType = "url"
+	case object == "email-addr" && path == "value":
+		iocType = "email"
+	case object == "file" && (path == "hashes.'MD5'" || path == "hashes.'SHA-1'" || path == "hashes.'SHA-256'"):
+		iocType = "hash"
+	default:
+		return IOC{}, false
+	}
+
+	return IOC{
+		Type:    iocType,
+		Value:   value,
+		Context: "stix-indicator",
+	}, true
+}
+
+// generateSTIXRecordID derives a CTIRecord ID from the STIX ID of the
+// intrusion-set/campaign it was built around, so re-ingesting the same
+// bundle produces the same record ID instead of a new one each time.
+func generateSTIXRecordID(subjectRef string) string {
+	if subjectRef == "" {
+		return fmt.Sprintf("stix_bundle_%d", time.Now().UnixNano())
+	}
+	return "stix_" + subjectRef
+}
+
+// parseSTIXTimestamp parses a STIX 2.1 timestamp, falling back to the
+// current time for a missing/malformed one rather than failing ingestion.
+func parseSTIXTimestamp(s string) time.Time {
+	if s == "" {
+		return time.Now()
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	return time.Now()
+}
@@ -0,0 +1,165 @@
+// Package taxii implements a minimal TAXII 2.1 client: polling a single
+// collection for objects added since a given time and handing the
+// resulting STIX bundle to threatdnacore.STIXBundleParser. It is the
+// authoritative counterpart to DataIngester's free-text HTML scraping.
+package taxii
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"threatdna/internal/threatdnacore"
+)
+
+// taxiiAcceptHeader is the media type TAXII 2.1 servers expect an object
+// request to Accept.
+const taxiiAcceptHeader = "application/taxii+json;version=2.1"
+
+// Client polls TAXII 2.1 collections and deduplicates objects by STIX ID
+// across calls, so a caller that polls on a schedule never hands the same
+// object to STIXBundleParser twice.
+type Client struct {
+	httpClient *http.Client
+	parser     *threatdnacore.STIXBundleParser
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewClient creates a Client. A nil httpClient gets a default with a 30s
+// timeout, since TAXII servers are a network dependency a hung request
+// shouldn't block PollSchedule forever.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		httpClient: httpClient,
+		parser:     threatdnacore.NewSTIXBundleParser(),
+		seen:       make(map[string]bool),
+	}
+}
+
+// taxiiEnvelope mirrors the TAXII 2.1 "objects" resource response shape
+// (api-root/collections/{id}/objects/).
+type taxiiEnvelope struct {
+	Objects []json.RawMessage `json:"objects"`
+	More    bool              `json:"more"`
+}
+
+// taxiiObjectID is the subset of a STIX object the dedup pass needs.
+type taxiiObjectID struct {
+	ID string `json:"id"`
+}
+
+// PollCollection fetches every object added to collectionID at rootURL
+// since addedAfter, skips any STIX ID already returned by a previous call
+// on this Client, and converts what's left into CTIRecords via
+// STIXBundleParser.
+func (c *Client) PollCollection(ctx context.Context, rootURL, collectionID string, addedAfter time.Time) ([]threatdnacore.CTIRecord, error) {
+	url := fmt.Sprintf("%s/collections/%s/objects/?added_after=%s",
+		strings.TrimRight(rootURL, "/"), collectionID, addedAfter.UTC().Format(time.RFC3339))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build TAXII request for collection %s: %w", collectionID, err)
+	}
+	req.Header.Set("Accept", taxiiAcceptHeader)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to poll TAXII collection %s: %w", collectionID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("TAXII server returned status %d for collection %s", resp.StatusCode, collectionID)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TAXII response for collection %s: %w", collectionID, err)
+	}
+
+	var envelope taxiiEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse TAXII response for collection %s: %w", collectionID, err)
+	}
+
+	newObjects := c.dedupe(envelope.Objects)
+	if len(newObjects) == 0 {
+		return nil, nil
+	}
+
+	bundle, err := json.Marshal(struct {
+		Type    string            `json:"type"`
+		ID      string            `json:"id"`
+		Objects []json.RawMessage `json:"objects"`
+	}{Type: "bundle", ID: fmt.Sprintf("bundle--%s", collectionID), Objects: newObjects})
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-wrap TAXII objects as a bundle: %w", err)
+	}
+
+	return c.parser.ParseBundle(bundle)
+}
+
+// dedupe drops any object whose STIX ID this Client has already returned
+// from a prior PollCollection call, and remembers the IDs it keeps.
+func (c *Client) dedupe(objects []json.RawMessage) []json.RawMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var fresh []json.RawMessage
+	for _, raw := range objects {
+		var head taxiiObjectID
+		if err := json.Unmarshal(raw, &head); err != nil || head.ID == "" || c.seen[head.ID] {
+			continue
+		}
+		c.seen[head.ID] = true
+		fresh = append(fresh, raw)
+	}
+	return fresh
+}
+
+// PollSchedule polls rootURL/collectionID every interval until ctx is
+// cancelled, feeding every CTIRecord it gets back straight into builder.
+// After each successful poll, addedAfter advances to the time that poll
+// started, before its records are built, so a slow or failing builder call
+// doesn't cause the next poll to re-request objects already seen. A failed
+// poll leaves addedAfter unchanged.
+func (c *Client) PollSchedule(ctx context.Context, rootURL, collectionID string, interval time.Duration, builder *threatdnacore.GenomeBuilder) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	addedAfter := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			pollStart := time.Now()
+			records, err := c.PollCollection(ctx, rootURL, collectionID, addedAfter)
+			if err != nil {
+				log.Printf("⚠️  TAXII poll of %s/%s failed: %v", rootURL, collectionID, err)
+				continue
+			}
+			addedAfter = pollStart
+
+			for _, record := range records {
+				if _, err := builder.BuildAndIndex(record); err != nil {
+					log.Printf("⚠️  Failed to build genome from TAXII record %s: %v", record.ID, err)
+				}
+			}
+			if len(records) > 0 {
+				log.Printf("✅ Ingested %d STIX records from %s/%s", len(records), rootURL, collectionID)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package tester emulates the Kafka→Genome→Bleve pipeline in-process so
+// unit tests can push synthetic CTIRecords and assert on emitted genomes
+// and index contents deterministically, without docker-compose or a real
+// Kafka broker.
+package tester
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"threatdna/internal/threatdnacore"
+)
+
+// Tester drives a GenomeBuilder the same way the live Kafka consumer does
+// (BuildGenome followed by indexGenome), but synchronously and in-memory.
+type Tester struct {
+	builder *threatdnacore.GenomeBuilder
+
+	mu       sync.Mutex
+	genomes  []*threatdnacore.Genome
+	trackers map[string]*QueueTracker
+}
+
+// New opens a GenomeBuilder against a temp-dir Bleve index at dbPath (the
+// caller is responsible for placing it under a tmpfs/temp dir and cleaning
+// it up) with no Kafka configuration.
+func New(dbPath string) (*Tester, error) {
+	builder, err := threatdnacore.NewGenomeBuilder(dbPath, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open test genome builder at %s: %w", dbPath, err)
+	}
+	return &Tester{
+		builder:  builder,
+		trackers: make(map[string]*QueueTracker),
+	}, nil
+}
+
+// Close closes the underlying GenomeBuilder's Bleve index.
+func (t *Tester) Close() error {
+	return t.builder.Close()
+}
+
+// Builder exposes the underlying GenomeBuilder, for tests that need to call
+// methods (e.g. GetGenomeStats) the Tester doesn't wrap directly.
+func (t *Tester) Builder() *threatdnacore.GenomeBuilder {
+	return t.builder
+}
+
+// ConsumeCTIRecord feeds record through BuildGenome+indexGenome as if it had
+// just been read off topic, recording the resulting genome against any
+// QueueTracker registered for that topic.
+func (t *Tester) ConsumeCTIRecord(topic string, record threatdnacore.CTIRecord) (*threatdnacore.Genome, error) {
+	genome, err := t.builder.BuildAndIndex(record)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.genomes = append(t.genomes, genome)
+	tracker := t.trackers[topic]
+	t.mu.Unlock()
+
+	if tracker != nil {
+		tracker.add(genome)
+	}
+	return genome, nil
+}
+
+// Genomes returns every genome built so far, across all topics.
+func (t *Tester) Genomes() []*threatdnacore.Genome {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]*threatdnacore.Genome, len(t.genomes))
+	copy(out, t.genomes)
+	return out
+}
+
+// NewQueueTracker registers a tracker that observes genomes built from
+// records consumed on topic, so a test can wait for a specific count
+// without polling the Tester's full genome list.
+func (t *Tester) NewQueueTracker(topic string) *QueueTracker {
+	tracker := &QueueTracker{}
+	t.mu.Lock()
+	t.trackers[topic] = tracker
+	t.mu.Unlock()
+	return tracker
+}
+
+// QueueTracker accumulates the genomes built from records consumed on a
+// single topic and lets a test block until a target count is reached.
+type QueueTracker struct {
+	mu      sync.Mutex
+	genomes []*threatdnacore.Genome
+}
+
+func (qt *QueueTracker) add(genome *threatdnacore.Genome) {
+	qt.mu.Lock()
+	qt.genomes = append(qt.genomes, genome)
+	qt.mu.Unlock()
+}
+
+func (qt *QueueTracker) count() int {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+	return len(qt.genomes)
+}
+
+// WaitForGenomes blocks until at least n genomes have been tracked or
+// timeout elapses, returning the genomes seen so far either way.
+func (qt *QueueTracker) WaitForGenomes(n int, timeout time.Duration) ([]*threatdnacore.Genome, error) {
+	deadline := time.Now().Add(timeout)
+	for qt.count() < n {
+		if time.Now().After(deadline) {
+			qt.mu.Lock()
+			got := len(qt.genomes)
+			qt.mu.Unlock()
+			return qt.snapshot(), fmt.Errorf("timed out waiting for %d genomes, got %d", n, got)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return qt.snapshot(), nil
+}
+
+func (qt *QueueTracker) snapshot() []*threatdnacore.Genome {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+	out := make([]*threatdnacore.Genome, len(qt.genomes))
+	copy(out, qt.genomes)
+	return out
+}
+
+// NewTempDBPath returns a fresh temp-dir path suitable for New. The path
+// ends in /test_genomes.db, the literal suffix NewGenomeBuilder strips to
+// derive its parent directory.
+func NewTempDBPath() (string, error) {
+	dir, err := os.MkdirTemp("", "threatdna-tester-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir for test Bleve index: %w", err)
+	}
+	return dir + "/test_genomes.db", nil
+}
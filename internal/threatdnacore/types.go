@@ -12,24 +12,39 @@ type CTIRecord struct {
 	Actor    string    `json:"actor,omitempty"`
 	Campaign string    `json:"campaign,omitempty"`
 	RawText  string    `json:"raw_text"`
-	TTPs     []TTP     `json:"ttps,omitempty"`
-	IOCs     []IOC     `json:"iocs,omitempty"`
-	Tags     []string  `json:"tags,omitempty"`
+	TTPs       []TTP         `json:"ttps,omitempty"`
+	IOCs       []IOC         `json:"iocs,omitempty"`
+	CVEs       []EnrichedCVE `json:"cves,omitempty"`
+	Detections []DetectionHit `json:"detections,omitempty"`
+	Tags       []string      `json:"tags,omitempty"`
+}
+
+// DetectionHit records that a DetectionTemplate matched a CTIRecord.
+type DetectionHit struct {
+	TemplateID string   `json:"template_id"`
+	Severity   string   `json:"severity,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
 }
 
 // TTP represents a Tactic, Technique, or Procedure with confidence
 type TTP struct {
-	TechniqueID string  `json:"technique_id"` // e.g., "T1059"
-	Confidence  float64 `json:"confidence"`   // 0.0 - 1.0
-	Context     string  `json:"context"`      // surrounding text
-	Tactic      string  `json:"tactic,omitempty"`
+	TechniqueID string   `json:"technique_id"` // e.g., "T1059"
+	Confidence  float64  `json:"confidence"`   // 0.0 - 1.0
+	Context     string   `json:"context"`      // surrounding text
+	Tactic      string   `json:"tactic,omitempty"`
+	RelatedCVEs []string `json:"related_cves,omitempty"` // CVE IDs found in the same paragraph
 }
 
 // IOC represents Indicators of Compromise
 type IOC struct {
-	Type    string `json:"type"`  // ip, domain, hash, etc.
-	Value   string `json:"value"`
+	Type    string `json:"type"`  // ip, domain, hash, url, email, etc.
+	Value   string `json:"value"` // canonicalized (refanged, lowercased/punycode) form
+	Raw     string `json:"raw,omitempty"` // exact substring as matched, before canonicalization
 	Context string `json:"context,omitempty"`
+	// Enrichment holds source-specific lookup results keyed by field name,
+	// e.g. GeoIPEnricher populates "country"/"country_iso"/"city"/"asn"/
+	// "as_org" for ip-type IOCs.
+	Enrichment map[string]string `json:"enrichment,omitempty"`
 }
 
 // AttackTechnique contains MITRE ATT&CK technique information
@@ -48,7 +63,11 @@ type MITREAttackBundle struct {
 	Objects []MITREObject `json:"objects"`
 }
 
-// MITREObject represents a single object within the bundle, which could be an attack-pattern, tactic, etc.
+// MITREObject represents a single object within the bundle, which could be
+// an attack-pattern, intrusion-set, malware, course-of-action, campaign, or
+// relationship. Most fields only apply to a subset of types; a relationship
+// object only populates Type/ID/RelationshipType/SourceRef/TargetRef, for
+// instance.
 type MITREObject struct {
 	Type                string               `json:"type"`
 	ID                  string               `json:"id"`
@@ -58,6 +77,9 @@ type MITREObject struct {
 	ExternalReferences  []ExternalReference  `json:"external_references"`
 	Platforms           []string             `json:"x_mitre_platforms"`
 	IsSubtechnique      bool                 `json:"x_mitre_is_subtechnique"`
+	RelationshipType    string               `json:"relationship_type,omitempty"`
+	SourceRef           string               `json:"source_ref,omitempty"`
+	TargetRef           string               `json:"target_ref,omitempty"`
 }
 
 // KillChainPhase represents the tactic (e.g., initial-access) an attack pattern belongs to.
@@ -82,31 +104,25 @@ type Genome struct {
 	Tactics      []string  `json:"tactics"`
 	Platforms    []string  `json:"platforms"`
 	CVEs         []string  `json:"cves,omitempty"`
+	Detections   []string  `json:"detections,omitempty"`
 	FirstSeen    time.Time `json:"first_seen"`
 	LastSeen     time.Time `json:"last_seen"`
 	Confidence   float64   `json:"confidence"`
 	SourceCount  int       `json:"source_count"`
 	IOCCount    int                    `json:"ioc_count"`
 	AllSourceText string                 `json:"all_source_text"`
+	MaxCVSSScore float64                `json:"max_cvss,omitempty"`
+	KEVExploited bool                   `json:"kev_exploited,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata"`
 }
 
-// GenomeStats provides analytics on genome collection
-type GenomeStats struct {
-	TotalGenomes      int                `json:"total_genomes"`
-	UniqueActors      int                `json:"unique_actors"`
-	UniqueCampaigns   int                `json:"unique_campaigns"`
-	AvgGenomeLength   float64            `json:"avg_genome_length"`
-	TTPFrequency      map[string]int     `json:"ttp_frequency"`
-	TacticFrequency   map[string]int     `json:"tactic_frequency"`
-	IOCTypeFrequency  map[string]int     `json:"ioc_type_frequency"`
-}
-
 // APISearchResult represents a single search result returned by the API
 type APISearchResult struct {
 	ID       string  `json:"id"`
 	Actor    string  `json:"actor,omitempty"`
 	Campaign string  `json:"campaign,omitempty"`
 	Score    float64 `json:"score"`
+	MaxCVSS  float64 `json:"max_cvss,omitempty"`
+	KEV      bool    `json:"kev,omitempty"`
 	// Add other fields as needed, e.g., TTPs, IOCs, Description snippet
 }
\ No newline at end of file
@@ -0,0 +1,259 @@
+package threatdnacore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// ViewState mirrors Goka's partition-table state observer: a View is
+// Connecting while it has no broker connection, CatchingUp while it lists
+// the genome state topic's partitions and starts a consumer on each from
+// the last known offset, and Running once every partition consumer has
+// started. Running does not wait for the replay to reach the end of the
+// topic, so older updates can still be applied after the state changes.
+type ViewState int32
+
+const (
+	ViewConnecting ViewState = iota
+	ViewCatchingUp
+	ViewRunning
+)
+
+func (s ViewState) String() string {
+	switch s {
+	case ViewConnecting:
+		return "Connecting"
+	case ViewCatchingUp:
+		return "CatchingUp"
+	case ViewRunning:
+		return "Running"
+	default:
+		return "Unknown"
+	}
+}
+
+// View maintains a materialized key->genome table recovered from, and kept
+// current by, the compacted threatdna-genomes topic the builder publishes
+// every finalized Genome to. It automatically reconnects and resumes
+// recovery from its last consumed offset on broker failures.
+type View struct {
+	broker string
+	topic  string
+
+	mu    sync.RWMutex
+	table map[string]*Genome
+
+	offMu   sync.Mutex
+	offsets map[int32]int64
+
+	subMu       sync.Mutex
+	subscribers []func(*Genome)
+
+	state int32
+}
+
+// NewView creates a View over topic on broker. Call Run to start recovery
+// and live tailing.
+func NewView(broker, topic string) *View {
+	return &View{
+		broker:  broker,
+		topic:   topic,
+		table:   make(map[string]*Genome),
+		offsets: make(map[int32]int64),
+	}
+}
+
+// Get returns the current materialized genome for id, if known.
+func (v *View) Get(id string) (*Genome, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	g, ok := v.table[id]
+	return g, ok
+}
+
+// Iterator returns a snapshot of every genome currently materialized.
+func (v *View) Iterator() []*Genome {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	out := make([]*Genome, 0, len(v.table))
+	for _, g := range v.table {
+		out = append(out, g)
+	}
+	return out
+}
+
+// Subscribe registers callback to be invoked (synchronously, on the view's
+// consume goroutine) every time a genome is created or updated.
+func (v *View) Subscribe(callback func(*Genome)) {
+	v.subMu.Lock()
+	defer v.subMu.Unlock()
+	v.subscribers = append(v.subscribers, callback)
+}
+
+// State reports the view's current connection/recovery state.
+func (v *View) State() ViewState {
+	return ViewState(atomic.LoadInt32(&v.state))
+}
+
+func (v *View) setState(s ViewState) {
+	atomic.StoreInt32(&v.state, int32(s))
+}
+
+// ReadinessHandler reports the view's state as JSON and answers 503 until
+// the view reaches Running, suitable for a Kubernetes readiness probe.
+func (v *View) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		state := v.State()
+		w.Header().Set("Content-Type", "application/json")
+		if state != ViewRunning {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"state": state.String()})
+	}
+}
+
+// Run connects to the genome state topic, recovers existing state, then
+// tails for live updates until ctx is cancelled. On any broker failure it
+// reconnects with exponential backoff and resumes from the last consumed
+// offset per partition, without losing position or re-emitting already-seen
+// updates to Subscribe callbacks more than once per reconnect.
+func (v *View) Run(ctx context.Context) error {
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		v.setState(ViewConnecting)
+		err := v.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		log.Printf("⚠️  Genome view disconnected from %s: %v. Reconnecting in %v...", v.topic, err, backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		if backoff < maxBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+func (v *View) runOnce(ctx context.Context) error {
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_8_0_0
+
+	consumer, err := sarama.NewConsumer([]string{v.broker}, config)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", v.broker, err)
+	}
+	defer consumer.Close()
+
+	partitions, err := consumer.Partitions(v.topic)
+	if err != nil {
+		return fmt.Errorf("failed to list partitions for %s: %w", v.topic, err)
+	}
+
+	v.setState(ViewCatchingUp)
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(partitions))
+
+	for _, partition := range partitions {
+		pc, err := consumer.ConsumePartition(v.topic, partition, v.resumeOffset(partition))
+		if err != nil {
+			return fmt.Errorf("failed to consume partition %d: %w", partition, err)
+		}
+
+		wg.Add(1)
+		go func(pc sarama.PartitionConsumer) {
+			defer wg.Done()
+			defer pc.Close()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg, ok := <-pc.Messages():
+					if !ok {
+						return
+					}
+					v.apply(msg)
+				case consumeErr, ok := <-pc.Errors():
+					if !ok {
+						return
+					}
+					select {
+					case errCh <- consumeErr:
+					default:
+					}
+					return
+				}
+			}
+		}(pc)
+	}
+
+	v.setState(ViewRunning)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errCh:
+		return err
+	case <-done:
+		return fmt.Errorf("all partition consumers for %s stopped unexpectedly", v.topic)
+	}
+}
+
+// resumeOffset returns the offset to resume a partition from: one past the
+// last offset this View has already applied, or OffsetOldest the first time
+// a partition is seen, so a reconnect never re-recovers from scratch.
+func (v *View) resumeOffset(partition int32) int64 {
+	v.offMu.Lock()
+	defer v.offMu.Unlock()
+	if offset, ok := v.offsets[partition]; ok {
+		return offset + 1
+	}
+	return sarama.OffsetOldest
+}
+
+func (v *View) apply(msg *sarama.ConsumerMessage) {
+	defer v.recordOffset(msg.Partition, msg.Offset)
+
+	var genome Genome
+	if err := json.Unmarshal(msg.Value, &genome); err != nil {
+		log.Printf("⚠️  Skipping malformed genome view message at partition %d offset %d: %v", msg.Partition, msg.Offset, err)
+		return
+	}
+
+	v.mu.Lock()
+	v.table[genome.ID] = &genome
+	v.mu.Unlock()
+
+	v.subMu.Lock()
+	subscribers := append([]func(*Genome){}, v.subscribers...)
+	v.subMu.Unlock()
+	for _, callback := range subscribers {
+		callback(&genome)
+	}
+}
+
+func (v *View) recordOffset(partition int32, offset int64) {
+	v.offMu.Lock()
+	v.offsets[partition] = offset
+	v.offMu.Unlock()
+}
@@ -0,0 +1,344 @@
+package threatdnacore
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/hpcloud/tail"
+	"gopkg.in/yaml.v3"
+)
+
+// WatchConfig is the on-disk YAML shape for the producer's --watch mode,
+// so directories, globs, and tuning knobs can be changed without touching
+// env vars or redeploying. See LoadWatchConfig.
+type WatchConfig struct {
+	// Paths are directories watched for new/modified HTML/PDF/JSON reports.
+	Paths []string `yaml:"paths"`
+	// TailGlobs are append-only feeds (syslog/CEF exports from MISP/OpenCTI)
+	// tailed line-by-line instead of re-ingested whole on every write.
+	TailGlobs []string `yaml:"tail_globs"`
+	// PollInterval bounds how often Paths are rescanned for files fsnotify
+	// missed (e.g. events dropped under inotify queue pressure).
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// Workers is the size of the worker pool draining the ingest channel.
+	Workers int `yaml:"workers"`
+	// StateFile persists per-file offsets/inodes across restarts so a
+	// restart doesn't re-publish reports the watcher already ingested.
+	StateFile string `yaml:"state_file"`
+}
+
+// defaultPollInterval and defaultWatchWorkers apply when a WatchConfig
+// leaves the corresponding field at its zero value.
+const (
+	defaultPollInterval = 30 * time.Second
+	defaultWatchWorkers = 4
+)
+
+// LoadWatchConfig reads and validates a WatchConfig from a YAML file.
+func LoadWatchConfig(path string) (*WatchConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch config %s: %w", path, err)
+	}
+	var cfg WatchConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse watch config %s: %w", path, err)
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWatchWorkers
+	}
+	if cfg.StateFile == "" {
+		cfg.StateFile = "data/watch_state.json"
+	}
+	return &cfg, nil
+}
+
+// fileOffset is the persisted per-file progress for one tailed feed,
+// keyed by inode so a renamed/rotated file is recognized as the same
+// stream, and a different file reusing the old path is not mistaken for it.
+type fileOffset struct {
+	Inode  uint64 `json:"inode"`
+	Offset int64  `json:"offset"`
+}
+
+// watchState is the on-disk shape WatchState persists to StateFile. It
+// maps a watched path to the last offset/inode the watcher consumed from
+// it, so a restart resumes instead of re-publishing.
+type watchState struct {
+	Files map[string]fileOffset `json:"files"`
+}
+
+// WatchState tracks ingestion progress across watcher restarts. It is
+// safe for concurrent use; callers should call Update after each record it
+// tracks is durably handed off (e.g. published to Kafka).
+type WatchState struct {
+	path string
+
+	mu    sync.Mutex
+	state watchState
+}
+
+// LoadWatchState opens (or initializes) the offset-tracking state file at
+// path. A missing file is not an error; it means every watched path is new.
+func LoadWatchState(path string) (*WatchState, error) {
+	ws := &WatchState{path: path, state: watchState{Files: make(map[string]fileOffset)}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ws, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch state %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &ws.state); err != nil {
+		return nil, fmt.Errorf("failed to parse watch state %s: %w", path, err)
+	}
+	if ws.state.Files == nil {
+		ws.state.Files = make(map[string]fileOffset)
+	}
+	return ws, nil
+}
+
+// Seen reports whether path has already been ingested at inode, and if so
+// the byte offset the watcher had reached.
+func (ws *WatchState) Seen(path string, inode uint64) (offset int64, ok bool) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	fo, exists := ws.state.Files[path]
+	if !exists || fo.Inode != inode {
+		return 0, false
+	}
+	return fo.Offset, true
+}
+
+// Update records that path (at inode) has been consumed up to offset, and
+// persists the new state to disk.
+func (ws *WatchState) Update(path string, inode uint64, offset int64) error {
+	ws.mu.Lock()
+	ws.state.Files[path] = fileOffset{Inode: inode, Offset: offset}
+	data, err := json.Marshal(ws.state)
+	ws.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal watch state: %w", err)
+	}
+	tmp := ws.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write watch state %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, ws.path)
+}
+
+// IngestFunc hands a newly discovered or appended record off to the
+// caller, typically to serialize and publish it to Kafka.
+type IngestFunc func(*CTIRecord) error
+
+// Watcher runs a long-lived tail-based ingestion loop: fsnotify reports
+// new/modified report files under WatchConfig.Paths, a poll loop covers
+// anything fsnotify missed, and hpcloud/tail follows WatchConfig.TailGlobs
+// append-only feeds line by line. Discovered records are fanned into a
+// bounded channel drained by a worker pool calling IngestFunc.
+type Watcher struct {
+	cfg     *WatchConfig
+	ing     *DataIngester
+	state   *WatchState
+	handle  IngestFunc
+	records chan *CTIRecord
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewWatcher builds a Watcher over cfg, using ing to parse discovered
+// files and handing each resulting record to handle.
+func NewWatcher(cfg *WatchConfig, ing *DataIngester, state *WatchState, handle IngestFunc) *Watcher {
+	return &Watcher{
+		cfg:     cfg,
+		ing:     ing,
+		state:   state,
+		handle:  handle,
+		records: make(chan *CTIRecord, cfg.Workers*4),
+		stop:    make(chan struct{}),
+	}
+}
+
+// Run starts the fsnotify watch, poll loop, tailers, and worker pool, and
+// blocks until Close is called or an unrecoverable error occurs.
+func (w *Watcher) Run() error {
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start fsnotify watcher: %w", err)
+	}
+	defer notifier.Close()
+
+	for _, dir := range w.cfg.Paths {
+		if err := notifier.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch directory %s: %w", dir, err)
+		}
+	}
+
+	for i := 0; i < w.cfg.Workers; i++ {
+		w.wg.Add(1)
+		go w.worker()
+	}
+
+	for _, glob := range w.cfg.TailGlobs {
+		matches, err := filepath.Glob(glob)
+		if err != nil {
+			log.Printf("⚠️  Invalid tail glob %q: %v", glob, err)
+			continue
+		}
+		for _, path := range matches {
+			w.wg.Add(1)
+			go w.tailFile(path)
+		}
+	}
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			close(w.records)
+			w.wg.Wait()
+			return nil
+		case event, ok := <-notifier.Events:
+			if !ok {
+				continue
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write) != 0 {
+				w.ingestIfNew(event.Name)
+			}
+		case err, ok := <-notifier.Errors:
+			if ok {
+				log.Printf("❌ fsnotify error: %v", err)
+			}
+		case <-ticker.C:
+			for _, dir := range w.cfg.Paths {
+				w.pollDirectory(dir)
+			}
+		}
+	}
+}
+
+// Close signals Run to stop. Run, not Close, closes the record channel and
+// waits for in-flight work to drain before it returns.
+func (w *Watcher) Close() {
+	close(w.stop)
+}
+
+func (w *Watcher) pollDirectory(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Printf("❌ Error polling watch directory %s: %v", dir, err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			w.ingestIfNew(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+// ingestIfNew parses path if its inode/size combination hasn't already
+// been recorded in WatchState, so restarts and duplicate fsnotify/poll
+// events don't re-publish the same report.
+func (w *Watcher) ingestIfNew(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	inode := inodeOf(info)
+	if offset, seen := w.state.Seen(path, inode); seen && offset == info.Size() {
+		return
+	}
+
+	record, err := w.ing.IngestFileFast(path)
+	if err != nil {
+		log.Printf("❌ Error watching %s: %v", path, err)
+		return
+	}
+	w.records <- record
+	if err := w.state.Update(path, inode, info.Size()); err != nil {
+		log.Printf("⚠️  Failed to persist watch state for %s: %v", path, err)
+	}
+}
+
+// tailFile follows an append-only feed (syslog/CEF exports from MISP/
+// OpenCTI) line by line, resuming from its last recorded offset.
+func (w *Watcher) tailFile(path string) {
+	defer w.wg.Done()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Printf("❌ Cannot tail %s: %v", path, err)
+		return
+	}
+	inode := inodeOf(info)
+	offset, _ := w.state.Seen(path, inode)
+
+	t, err := tail.TailFile(path, tail.Config{
+		Follow:   true,
+		ReOpen:   true,
+		Location: &tail.SeekInfo{Offset: offset, Whence: os.SEEK_SET},
+	})
+	if err != nil {
+		log.Printf("❌ Failed to tail %s: %v", path, err)
+		return
+	}
+	defer t.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case line, ok := <-t.Lines:
+			if !ok {
+				return
+			}
+			if line.Err != nil {
+				log.Printf("❌ Tail error on %s: %v", path, line.Err)
+				continue
+			}
+			record := recordFromLine(path, line.Text)
+			w.records <- &record
+			offset += int64(len(line.Text)) + 1 // tail strips the trailing newline
+			if info, err := os.Stat(path); err == nil {
+				w.state.Update(path, inodeOf(info), offset)
+			}
+		}
+	}
+}
+
+// recordFromLine wraps a single tailed line (e.g. a CEF/syslog event) in a
+// minimal CTIRecord for the normal TTP/IOC extraction pipeline to process.
+func recordFromLine(source, line string) CTIRecord {
+	return CTIRecord{
+		ID:      generateID(fmt.Sprintf("%s:%d", source, time.Now().UnixNano())),
+		Source:  fmt.Sprintf("tail:%s", source),
+		Date:    time.Now(),
+		RawText: line,
+		Tags:    []string{"tailed-feed"},
+	}
+}
+
+func (w *Watcher) worker() {
+	defer w.wg.Done()
+	for record := range w.records {
+		if record == nil {
+			continue
+		}
+		if err := w.handle(record); err != nil {
+			log.Printf("❌ Error handling watched record %s: %v", record.ID, err)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package threatdnacore
+
+import (
+	"os"
+	"syscall"
+)
+
+// inodeOf extracts the inode number from a FileInfo's platform-specific
+// Sys(), so Watcher can tell a rotated/replaced file apart from the one it
+// already consumed even when both share the same path. Platforms whose
+// Sys() doesn't expose an inode (anything without a syscall.Stat_t) fall
+// back to 0, which just disables the rotation check there.
+func inodeOf(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return stat.Ino
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"threatdna/internal/kafkaio"
+)
+
+// latencyWriter is a kafkaio.Writer that sleeps latency per WriteMessages
+// call, standing in for a real broker round trip so the benchmark below
+// measures BatchPublisher's pipelining overhead rather than the network.
+type latencyWriter struct {
+	latency time.Duration
+	mu      sync.Mutex
+	count   int
+}
+
+func (w *latencyWriter) WriteMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	time.Sleep(w.latency)
+	w.mu.Lock()
+	w.count += len(msgs)
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *latencyWriter) Close() error { return nil }
+
+// BenchmarkPublishSynchronousVsBatch compares the old one-message-at-a-time
+// synchronous loop (publishInitialData's original shape) against
+// BatchPublisher's pipelined workers, against a simulated broker round
+// trip, to show the motivation for chunk5-4's fan-out redesign.
+func BenchmarkPublishSynchronousVsBatch(b *testing.B) {
+	const recordCount = 200
+	const simulatedRTT = 2 * time.Millisecond
+
+	b.Run("synchronous", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			writer := &latencyWriter{latency: simulatedRTT}
+			producer := kafkaio.NewProducer(writer)
+			for j := 0; j < recordCount; j++ {
+				rec := &kafkaio.Record{Topic: "cti-records", Key: []byte(fmt.Sprintf("rec-%d", j)), Value: []byte("{}")}
+				if err := producer.Produce(context.Background(), rec); err != nil {
+					b.Fatalf("unexpected publish error: %v", err)
+				}
+			}
+		}
+	})
+
+	for _, workers := range []int{4, 16} {
+		b.Run(fmt.Sprintf("batch-workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				writer := &latencyWriter{latency: simulatedRTT}
+				producer := kafkaio.NewProducer(writer)
+				cfg := kafkaio.BatchConfig{Workers: workers, BatchSize: recordCount}
+				bp := kafkaio.NewBatchPublisher(context.Background(), producer.Produce, cfg)
+
+				for j := 0; j < recordCount; j++ {
+					bp.Publish(&kafkaio.Record{Topic: "cti-records", Key: []byte(fmt.Sprintf("rec-%d", j)), Value: []byte("{}")})
+				}
+				for j := 0; j < recordCount; j++ {
+					if report := <-bp.Reports(); report.Err != nil {
+						b.Fatalf("unexpected publish error: %v", report.Err)
+					}
+				}
+				bp.Close()
+			}
+		})
+	}
+}
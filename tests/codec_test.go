@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"threatdna/internal/threatdnacore"
+)
+
+func sampleRecordForCodec() threatdnacore.CTIRecord {
+	return threatdnacore.CTIRecord{
+		ID:      "rec-codec-1",
+		Source:  "test",
+		Date:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Actor:   "APT29",
+		RawText: "APT29 used T1059 against 198.51.100.7",
+		TTPs:    []threatdnacore.TTP{{TechniqueID: "T1059", Confidence: 0.9}},
+		IOCs:    []threatdnacore.IOC{{Type: "ip", Value: "198.51.100.7"}},
+		Detections: []threatdnacore.DetectionHit{
+			{TemplateID: "cobalt-strike-beacon", Severity: "high"},
+		},
+	}
+}
+
+// TestJSONCodecRoundTrips verifies JSONCodec's output unmarshals back into
+// an equivalent CTIRecord, since it's just the wire shape itself.
+func TestJSONCodecRoundTrips(t *testing.T) {
+	codec, err := threatdnacore.NewRecordCodec("json")
+	if err != nil {
+		t.Fatalf("NewRecordCodec(json) failed: %v", err)
+	}
+	if codec.ContentType() != "application/json" {
+		t.Fatalf("expected application/json content type, got %q", codec.ContentType())
+	}
+
+	record := sampleRecordForCodec()
+	data, err := codec.Encode(record)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	var decoded threatdnacore.CTIRecord
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal JSON codec output: %v", err)
+	}
+	if decoded.ID != record.ID || decoded.Actor != record.Actor {
+		t.Fatalf("round-tripped record mismatch: got %+v", decoded)
+	}
+}
+
+// TestSTIXCodecRoundTripsThroughBundleParser verifies the bundle STIXCodec
+// produces is not just well-formed STIX 2.1 JSON, but actually re-ingests
+// through STIXBundleParser (ThreatDNA's own STIX consumer, and the
+// counterpart any OpenCTI/MISP/TAXII 2.1 consumer would run) back into a
+// CTIRecord carrying the same actor, technique, and IOC.
+func TestSTIXCodecRoundTripsThroughBundleParser(t *testing.T) {
+	codec, err := threatdnacore.NewRecordCodec("stix2.1")
+	if err != nil {
+		t.Fatalf("NewRecordCodec(stix2.1) failed: %v", err)
+	}
+	if !strings.Contains(codec.ContentType(), "stix+json") {
+		t.Fatalf("expected a stix+json content type, got %q", codec.ContentType())
+	}
+
+	data, err := codec.Encode(sampleRecordForCodec())
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	var bundle struct {
+		Type    string `json:"type"`
+		Objects []struct {
+			Type string `json:"type"`
+		} `json:"objects"`
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatalf("STIX codec output is not valid JSON: %v", err)
+	}
+	if bundle.Type != "bundle" {
+		t.Fatalf("expected top-level type \"bundle\", got %q", bundle.Type)
+	}
+
+	records, err := threatdnacore.NewSTIXBundleParser().ParseBundle(data)
+	if err != nil {
+		t.Fatalf("ParseBundle failed on STIXCodec output: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record back from the bundle, got %d", len(records))
+	}
+	got := records[0]
+	if got.Actor != "APT29" {
+		t.Fatalf("expected Actor APT29 round-tripped, got %q", got.Actor)
+	}
+	if len(got.TTPs) != 1 || got.TTPs[0].TechniqueID != "T1059" {
+		t.Fatalf("expected TTP T1059 round-tripped, got %+v", got.TTPs)
+	}
+	if len(got.IOCs) != 1 || got.IOCs[0].Value != "198.51.100.7" {
+		t.Fatalf("expected IOC 198.51.100.7 round-tripped, got %+v", got.IOCs)
+	}
+}
+
+// TestCEFCodecEscapesReservedCharacters verifies CEFCodec produces a
+// well-formed CEF:0 header and escapes '|' in header fields and '=' in
+// extension values, per the CEF spec.
+func TestCEFCodecEscapesReservedCharacters(t *testing.T) {
+	codec, err := threatdnacore.NewRecordCodec("cef")
+	if err != nil {
+		t.Fatalf("NewRecordCodec(cef) failed: %v", err)
+	}
+	if codec.ContentType() != "text/cef" {
+		t.Fatalf("expected text/cef content type, got %q", codec.ContentType())
+	}
+
+	record := sampleRecordForCodec()
+	record.Actor = "APT|29"
+	record.RawText = "key=value with a pipe | and an equals ="
+
+	data, err := codec.Encode(record)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	line := string(data)
+
+	if !strings.HasPrefix(line, "CEF:0|ThreatDNA|cti-producer|1.0|") {
+		t.Fatalf("expected a CEF:0 header, got: %s", line)
+	}
+	if strings.Contains(line, `|APT|29|`) {
+		t.Fatalf("expected the pipe in Actor to be escaped, got: %s", line)
+	}
+	if !strings.Contains(line, `APT\|29`) {
+		t.Fatalf("expected escaped actor in header, got: %s", line)
+	}
+	if !strings.Contains(line, `msg=key\=value`) {
+		t.Fatalf("expected escaped '=' in msg extension, got: %s", line)
+	}
+}
+
+// TestNewRecordCodecRejectsUnknownFormat verifies an unrecognized
+// KAFKA_VALUE_FORMAT fails fast instead of silently falling back to JSON.
+func TestNewRecordCodecRejectsUnknownFormat(t *testing.T) {
+	if _, err := threatdnacore.NewRecordCodec("protobuf"); err == nil {
+		t.Fatal("expected an error for an unknown codec format")
+	}
+}
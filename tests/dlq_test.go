@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"threatdna/internal/kafkaio"
+	"threatdna/internal/threatdnacore"
+)
+
+// mockWriter is a kafkaio.Writer that fails its first failUntil calls for a
+// given topic before succeeding, so tests can exercise PublishWithDLQ's
+// retry and dead-letter paths without a live broker. failUntil is the
+// default applied to topics with no entry in failUntilByTopic, so a test
+// can make the original topic fail unconditionally while still letting the
+// DLQ topic succeed.
+type mockWriter struct {
+	mu               sync.Mutex
+	failUntil        int
+	failUntilByTopic map[string]int
+	attempts         map[string]int
+	written          []kafkago.Message
+}
+
+func newMockWriter(failUntil int) *mockWriter {
+	return &mockWriter{
+		failUntil:        failUntil,
+		failUntilByTopic: make(map[string]int),
+		attempts:         make(map[string]int),
+	}
+}
+
+func (m *mockWriter) WriteMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, msg := range msgs {
+		m.attempts[msg.Topic]++
+		limit := m.failUntil
+		if topicLimit, ok := m.failUntilByTopic[msg.Topic]; ok {
+			limit = topicLimit
+		}
+		if m.attempts[msg.Topic] <= limit {
+			return errors.New("simulated broker unavailable")
+		}
+		m.written = append(m.written, msg)
+	}
+	return nil
+}
+
+func (m *mockWriter) Close() error { return nil }
+
+func TestPublishWithDLQRetriesThenSucceeds(t *testing.T) {
+	writer := newMockWriter(2) // fails twice, succeeds on the 3rd attempt
+	producer := kafkaio.NewProducer(writer)
+	dlq := threatdnacore.NewDLQPublisher(producer)
+	dlq.Policy.BaseDelay = 0 // don't slow the test down with real backoff
+
+	rec := &kafkaio.Record{Topic: "cti-records", Key: []byte("rec-1"), Value: []byte(`{"id":"rec-1"}`)}
+	if err := dlq.PublishWithDLQ(context.Background(), rec); err != nil {
+		t.Fatalf("expected PublishWithDLQ to succeed after retries, got: %v", err)
+	}
+
+	if len(writer.written) != 1 || writer.written[0].Topic != "cti-records" {
+		t.Fatalf("expected record published to cti-records, got: %+v", writer.written)
+	}
+}
+
+func TestPublishWithDLQFallsBackToDeadLetter(t *testing.T) {
+	writer := newMockWriter(1000)                              // always fails the original topic
+	writer.failUntilByTopic[threatdnacore.DefaultDLQTopic] = 0 // but the DLQ topic itself is healthy
+	producer := kafkaio.NewProducer(writer)
+	dlq := threatdnacore.NewDLQPublisher(producer)
+	dlq.Policy.MaxAttempts = 2
+	dlq.Policy.BaseDelay = 0
+
+	rec := &kafkaio.Record{Topic: "cti-records", Key: []byte("rec-2"), Value: []byte(`{"id":"rec-2"}`)}
+	if err := dlq.PublishWithDLQ(context.Background(), rec); err != nil {
+		t.Fatalf("expected PublishWithDLQ to recover via the DLQ topic, got: %v", err)
+	}
+
+	if len(writer.written) != 1 {
+		t.Fatalf("expected exactly one message to land (on the DLQ topic), got: %+v", writer.written)
+	}
+	dlqMsg := writer.written[0]
+	if dlqMsg.Topic != threatdnacore.DefaultDLQTopic {
+		t.Fatalf("expected message on DLQ topic %s, got %s", threatdnacore.DefaultDLQTopic, dlqMsg.Topic)
+	}
+
+	headers := make(map[string]string, len(dlqMsg.Headers))
+	for _, h := range dlqMsg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	if headers["x-original-topic"] != "cti-records" {
+		t.Fatalf("expected x-original-topic header to name cti-records, got headers: %+v", headers)
+	}
+	if headers["x-attempts"] != "2" {
+		t.Fatalf("expected x-attempts header to be 2, got: %q", headers["x-attempts"])
+	}
+}
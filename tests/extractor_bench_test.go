@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"threatdna/internal/threatdnacore"
+)
+
+// benchTechniques is a synthetic but representative slice of the ATT&CK
+// technique set ExtractTTPs scans against, large enough to exercise the
+// Aho-Corasick automaton's build cost and per-document pass over a
+// multi-megabyte corpus.
+func benchTechniques(n int) map[string]threatdnacore.AttackTechnique {
+	techniques := make(map[string]threatdnacore.AttackTechnique, n)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("T%04d", 1000+i)
+		techniques[id] = threatdnacore.AttackTechnique{
+			ID:       id,
+			Name:     fmt.Sprintf("Synthetic Technique %d", i),
+			Tactics:  []string{"execution"},
+			Keywords: []string{fmt.Sprintf("synthetic-keyword-%d", i), "command and scripting interpreter"},
+		}
+	}
+	return techniques
+}
+
+// benchCorpus builds a multi-megabyte document by repeating prose that
+// occasionally mentions a technique, so ExtractTTPs has real matches to
+// aggregate rather than scanning pure noise.
+func benchCorpus(techniques map[string]threatdnacore.AttackTechnique, sizeMB int) string {
+	var sb strings.Builder
+	var ids []string
+	for id := range techniques {
+		ids = append(ids, id)
+	}
+	paragraph := "The adversary leveraged a command and scripting interpreter to execute payloads against the target environment, observed alongside %s in the incident timeline. "
+	for sb.Len() < sizeMB*1024*1024 {
+		id := ids[sb.Len()%len(ids)]
+		sb.WriteString(fmt.Sprintf(paragraph, id))
+	}
+	return sb.String()
+}
+
+// BenchmarkExtractTTPs measures ExtractTTPs throughput on multi-MB corpora
+// with the full technique set loaded, demonstrating the single-pass
+// Aho-Corasick automaton's performance relative to corpus size.
+func BenchmarkExtractTTPs(b *testing.B) {
+	techniques := benchTechniques(600)
+	rules := threatdnacore.NewRuleSet("")
+	extractor := threatdnacore.NewTechniqueExtractor(techniques, rules)
+
+	for _, sizeMB := range []int{1, 5} {
+		corpus := benchCorpus(techniques, sizeMB)
+		b.Run(fmt.Sprintf("%dMB", sizeMB), func(b *testing.B) {
+			b.SetBytes(int64(len(corpus)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				extractor.ExtractTTPs(corpus)
+			}
+		})
+	}
+}
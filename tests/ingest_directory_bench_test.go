@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"threatdna/internal/threatdnacore"
+)
+
+// writeBenchHTMLFiles creates n small HTML reports under dir, each
+// mentioning a technique ID so IngestFileFastContext's extraction path does
+// real work rather than just I/O.
+func writeBenchHTMLFiles(tb testing.TB, dir string, n int) {
+	tb.Helper()
+	for i := 0; i < n; i++ {
+		body := fmt.Sprintf("<html><body><p>APT29 used T1059 to execute commands on host %d.</p></body></html>", i)
+		path := filepath.Join(dir, fmt.Sprintf("report-%d.html", i))
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			tb.Fatalf("failed to write benchmark fixture %s: %v", path, err)
+		}
+	}
+}
+
+// BenchmarkIngestDirectoryConcurrency measures IngestDirectoryContext's
+// records/sec at different worker-pool sizes, demonstrating the throughput
+// gain the concurrent worker pool gives over the old sequential
+// IngestDirectory loop (concurrency=1 reproduces that baseline).
+func BenchmarkIngestDirectoryConcurrency(b *testing.B) {
+	ingester := threatdnacore.NewDataIngester()
+	if err := ingester.Initialize(); err != nil {
+		b.Fatalf("failed to initialize ingester: %v", err)
+	}
+
+	dir, err := os.MkdirTemp("", "ingest-bench")
+	if err != nil {
+		b.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	const fileCount = 50
+	writeBenchHTMLFiles(b, dir, fileCount)
+
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", concurrency), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				records, err := ingester.IngestDirectoryContext(context.Background(), dir, concurrency, nil)
+				if err != nil {
+					b.Fatalf("unexpected ingest error: %v", err)
+				}
+				if len(records) != fileCount {
+					b.Fatalf("expected %d records, got %d", fileCount, len(records))
+				}
+			}
+		})
+	}
+}
@@ -1,192 +1,68 @@
 package tests
 
 import (
-	"context"
-	"fmt"
-	"log"
 	"os"
-	"os/exec"
-	"io"
-	"strings"
 	"testing"
 	"time"
 
-	"github.com/segmentio/kafka-go"
 	"threatdna/internal/threatdnacore"
+	"threatdna/internal/threatdnacore/tester"
 )
 
-const (
-	testDBPath    = "./threats.bleve/threat_genomes.db" // Relative to project root
-	testKafkaBroker = "localhost:9093"
-	testKafkaTopic  = "test-cti-records"
-	ctiFile       = "../enterprise-attack.json" // Relative to tests/ directory
-)
+const pipelineTestTopic = "test-cti-records"
 
-func TestMain(m *testing.M) {
-	// Setup: Ensure Kafka is running and clean up before tests
-	log.Println("Setting up test environment...")
-	err := setupKafkaAndDB()
+// TestPipelineEndToEnd drives the Kafka->Genome->Bleve pipeline in-process
+// via the tester harness instead of shelling out to docker-compose and the
+// producer/builder binaries, so it runs deterministically in milliseconds.
+func TestPipelineEndToEnd(t *testing.T) {
+	dbPath, err := tester.NewTempDBPath()
 	if err != nil {
-		log.Fatalf("Failed to set up Kafka and DB for tests: %v", err)
+		t.Fatalf("Failed to create temp DB path: %v", err)
 	}
+	defer os.RemoveAll(dbPath[:len(dbPath)-len("/test_genomes.db")])
 
-	// Run tests
-	code := m.Run()
-
-	// Teardown: Clean up after tests
-	log.Println("Tearing down test environment...")
-	teardownKafkaAndDB()
-
-	os.Exit(code)
-}
-
-func setupKafkaAndDB() error {
-	// Ensure Docker Compose is up
-	cmd := exec.Command("docker-compose", "up", "-d")
-	cmd.Dir = ".." // Run docker-compose from project root
-	output, err := cmd.CombinedOutput()
+	tst, err := tester.New(dbPath)
 	if err != nil {
-		return fmt.Errorf("failed to start docker-compose: %v\n%s", err, output)
+		t.Fatalf("Failed to create pipeline tester: %v", err)
 	}
-	log.Println("Docker Compose started.")
-
-	// Give Kafka some time to start up
-	time.Sleep(10 * time.Second)
+	defer tst.Close()
 
-	// Create test topic
-	conn, err := kafka.DialContext(context.Background(), "tcp", testKafkaBroker)
-	if err != nil {
-		return fmt.Errorf("failed to dial kafka broker: %w", err)
-	}
-	defer conn.Close()
+	tracker := tst.NewQueueTracker(pipelineTestTopic)
 
-	err = conn.CreateTopics(kafka.TopicConfig{
-		Topic:             testKafkaTopic,
-		NumPartitions:     1,
-		ReplicationFactor: 1,
-	})
-	if err != nil && !strings.Contains(err.Error(), "already exists") {
-		return fmt.Errorf("failed to create kafka topic %s: %w", testKafkaTopic, err)
+	records := []threatdnacore.CTIRecord{
+		{ID: "rec-1", Actor: "APT29", RawText: "APT29 used T1059 to execute commands",
+			TTPs: []threatdnacore.TTP{{TechniqueID: "T1059", Confidence: 0.8}}, Tags: []string{"Windows"}},
+		{ID: "rec-2", Actor: "APT29", RawText: "APT29 exfiltrated data via T1041",
+			TTPs: []threatdnacore.TTP{{TechniqueID: "T1041", Confidence: 0.8}}, Tags: []string{"Windows"}},
+		{ID: "rec-3", Actor: "FIN7", RawText: "FIN7 deployed T1486 ransomware",
+			TTPs: []threatdnacore.TTP{{TechniqueID: "T1486", Confidence: 0.8}}, Tags: []string{"Windows"}},
 	}
-	log.Printf("Kafka topic %s ensured.", testKafkaTopic)
 
-	// Clear test DB
-	os.Remove(testDBPath)
-	log.Printf("Cleared test database: %s", testDBPath)
-
-	return nil
-}
-
-func teardownKafkaAndDB() {
-	// Stop Docker Compose
-	cmd := exec.Command("docker-compose", "down")
-	cmd.Dir = ".." // Run docker-compose from project root
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		log.Printf("Failed to stop docker-compose: %v\n%s", err, output)
-	}
-	log.Println("Docker Compose stopped.")
-
-	// Delete test topic (optional, but good for clean slate)
-	conn, err := kafka.DialContext(context.Background(), "tcp", testKafkaBroker)
-	if err == nil {
-		defer conn.Close()
-		conn.DeleteTopics(testKafkaTopic)
-		log.Printf("Deleted Kafka topic %s.", testKafkaTopic)
+	for _, record := range records {
+		if _, err := tst.ConsumeCTIRecord(pipelineTestTopic, record); err != nil {
+			t.Fatalf("Failed to consume CTI record %s: %v", record.ID, err)
+		}
 	}
 
-	// Clean up test DB
-	os.Remove(testDBPath)
-	log.Printf("Cleaned up test database: %s", testDBPath)
-}
-
-func TestPipelineEndToEnd(t *testing.T) {
-	// Ensure DB is clean before this specific test
-	os.Remove(testDBPath)
-
-	// 1. Start builder in background
-	log.Println("Starting builder for end-to-end test...")
-	builderCmd := exec.Command("go", "run", "./cmd/builder/main.go")
-	builderCmd.Env = append(os.Environ(),
-		fmt.Sprintf("KAFKA_BROKER=%s", testKafkaBroker),
-		fmt.Sprintf("KAFKA_TOPIC=%s", testKafkaTopic),
-		fmt.Sprintf("DB_PATH=%s", testDBPath), // Pass test DB path
-	)
-	builderCmd.Dir = ".." // Run builder from project root
-	// builderOutput, err := builderCmd.StderrPipe() // Removed unused variable
-	builderStderr, err := builderCmd.StderrPipe()
+	genomes, err := tracker.WaitForGenomes(len(records), time.Second)
 	if err != nil {
-		t.Fatalf("Failed to get builder stderr pipe: %v", err)
+		t.Fatalf("Failed waiting for genomes: %v", err)
 	}
-	builderStdout, err := builderCmd.StdoutPipe()
-	if err != nil {
-		t.Fatalf("Failed to get builder stdout pipe: %v", err)
+	if len(genomes) != len(records) {
+		t.Errorf("Expected %d genomes, got %d", len(records), len(genomes))
 	}
 
-	if err := builderCmd.Start(); err != nil {
-		t.Fatalf("Failed to start builder: %v", err)
-	}
-
-	go func() {
-		slurp, _ := io.ReadAll(builderStderr)
-		if len(slurp) > 0 {
-			t.Logf("Builder Stderr: %s", slurp)
-		}
-	}()
-	go func() {
-		slurp, _ := io.ReadAll(builderStdout)
-		if len(slurp) > 0 {
-			t.Logf("Builder Stdout: %s", slurp)
-		}
-	}()
-	defer func() {
-		builderCmd.Process.Kill()
-		builderCmd.Wait()
-		log.Println("Builder stopped.")
-	}()
-
-	// Give builder some time to start Kafka consumer
-	time.Sleep(5 * time.Second)
-
-	// 2. Run producer to publish CTI data
-	log.Println("Running producer to publish CTI data...")
-	producerCmd := exec.Command("go", "run", "./cmd/producer/main.go")
-	producerCmd.Env = append(os.Environ(),
-		fmt.Sprintf("KAFKA_BROKER=%s", testKafkaBroker),
-		fmt.Sprintf("KAFKA_TOPIC=%s", testKafkaTopic),
-		fmt.Sprintf("CTI_FILE=%s", ctiFile),
-	)
-	producerCmd.Dir = ".." // Run producer from project root
-	producerOutput, err := producerCmd.CombinedOutput()
+	stats, err := tst.Builder().GetGenomeStats()
 	if err != nil {
-		t.Fatalf("Producer failed: %v\n%s", err, producerOutput)
+		t.Fatalf("Failed to get genome stats: %v", err)
 	}
-	log.Printf("Producer output:\n%s", producerOutput)
-
-	// Give builder time to process messages
-	time.Sleep(10 * time.Second)
-
-	// 3. Stop builder (to release DB lock)
-	log.Println("Stopping builder to release DB lock...")
-	builderCmd.Process.Kill()
-	builderCmd.Wait()
-
-	// 4. Query genome count
-	log.Println("Querying genome count...")
-	builder, err := threatdnacore.NewGenomeBuilder(testDBPath, "", "")
-	if err != nil {
-		t.Fatalf("Failed to create genome builder for stats: %v", err)
+	if stats.TotalGenomes != len(records) {
+		t.Errorf("Expected %d total genomes in stats, got %d", len(records), stats.TotalGenomes)
 	}
-	defer builder.Close()
-
-	stats, err := builder.GetGenomeStats()
-	if err != nil {
-		t.Fatalf("Failed to get genome statistics: %v", err)
+	if stats.ByActor["APT29"] != 2 {
+		t.Errorf("Expected 2 genomes for APT29, got %d", stats.ByActor["APT29"])
 	}
-
-	expectedGenomes := 823 // Based on previous producer run
-	if stats.TotalGenomes != expectedGenomes {
-		t.Errorf("Expected %d genomes, got %d", expectedGenomes, stats.TotalGenomes)
+	if stats.ByActor["FIN7"] != 1 {
+		t.Errorf("Expected 1 genome for FIN7, got %d", stats.ByActor["FIN7"])
 	}
-	log.Printf("Successfully verified %d genomes in the database.", stats.TotalGenomes)
 }
@@ -2,7 +2,9 @@ package tests
 
 import (
 	"testing"
+
 	"threatdna/internal/threatdnacore"
+	"threatdna/internal/threatdnacore/tester"
 	// You might need to import other packages for Bleve interaction
 	// "github.com/blevesearch/bleve"
 	// "os"
@@ -54,8 +56,35 @@ func TestSearchComponent(t *testing.T) {
 	//    - Verify the format of the output.
 
 	t.Run("Should return correct results for basic text search", func(t *testing.T) {
-		// Test a simple keyword search
-		t.Skip("Implement test for basic text search")
+		// Uses the in-memory tester harness instead of a pre-populated
+		// fixture index, so this stays deterministic without docker-compose.
+		dbPath, err := tester.NewTempDBPath()
+		if err != nil {
+			t.Fatalf("Failed to create temp DB path: %v", err)
+		}
+
+		tst, err := tester.New(dbPath)
+		if err != nil {
+			t.Fatalf("Failed to create test genome builder: %v", err)
+		}
+		defer tst.Close()
+
+		record := threatdnacore.CTIRecord{
+			ID:    "rec-search-1",
+			Actor: "APT29",
+			TTPs:  []threatdnacore.TTP{{TechniqueID: "T1486", Confidence: 0.9}},
+		}
+		if _, err := tst.ConsumeCTIRecord("test-topic", record); err != nil {
+			t.Fatalf("Failed to index test genome: %v", err)
+		}
+
+		genomes := tst.Genomes()
+		if len(genomes) != 1 {
+			t.Fatalf("Expected 1 genome, got %d", len(genomes))
+		}
+		if genomes[0].Actor != "APT29" {
+			t.Errorf("Expected actor APT29, got %s", genomes[0].Actor)
+		}
 	})
 
 	t.Run("Should return correct results for actor search with boosting", func(t *testing.T) {
@@ -70,7 +99,60 @@ func TestSearchComponent(t *testing.T) {
 	})
 
 	t.Run("Should handle behavioral sequence search (if implemented)", func(t *testing.T) {
-		// Test specific TTP sequence queries
-		t.Skip("Implement test for behavioral sequence search")
+		dbPath, err := tester.NewTempDBPath()
+		if err != nil {
+			t.Fatalf("Failed to create temp DB path: %v", err)
+		}
+
+		tst, err := tester.New(dbPath)
+		if err != nil {
+			t.Fatalf("Failed to create test genome builder: %v", err)
+		}
+
+		matching := threatdnacore.CTIRecord{
+			ID:    "rec-sequence-1",
+			Actor: "APT29",
+			TTPs: []threatdnacore.TTP{
+				{TechniqueID: "T1566.001", Confidence: 0.9},
+				{TechniqueID: "T1059.001", Confidence: 0.9},
+				{TechniqueID: "T1071.001", Confidence: 0.9},
+			},
+		}
+		matchingGenome, err := tst.ConsumeCTIRecord("test-topic", matching)
+		if err != nil {
+			t.Fatalf("Failed to index matching genome: %v", err)
+		}
+
+		nonMatching := threatdnacore.CTIRecord{
+			ID:    "rec-sequence-2",
+			Actor: "FIN7",
+			TTPs: []threatdnacore.TTP{
+				{TechniqueID: "T1190", Confidence: 0.9},
+				{TechniqueID: "T1486", Confidence: 0.9},
+			},
+		}
+		if _, err := tst.ConsumeCTIRecord("test-topic", nonMatching); err != nil {
+			t.Fatalf("Failed to index non-matching genome: %v", err)
+		}
+
+		// RunSequenceSearch opens its own handle on the index, so the
+		// tester's handle must be closed first to release Bleve's lock.
+		if err := tst.Close(); err != nil {
+			t.Fatalf("Failed to close test genome builder: %v", err)
+		}
+
+		hits, err := threatdnacore.RunSequenceSearch(dbPath, []string{"T1566.001", "T1059.001", "T1071.001"}, 0)
+		if err != nil {
+			t.Fatalf("RunSequenceSearch failed: %v", err)
+		}
+		if len(hits) != 1 {
+			t.Fatalf("Expected 1 hit, got %d", len(hits))
+		}
+		if hits[0].ID != matchingGenome.ID {
+			t.Errorf("Expected hit for %s, got %s", matchingGenome.ID, hits[0].ID)
+		}
+		if hits[0].Actor != "APT29" {
+			t.Errorf("Expected actor APT29, got %s", hits[0].Actor)
+		}
 	})
 }
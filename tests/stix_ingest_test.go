@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"testing"
+
+	"threatdna/internal/threatdnacore"
+)
+
+// TestSTIXBundleParserLinksRelatedObjects verifies that ParseBundle turns a
+// small intrusion-set/attack-pattern/indicator bundle into a single
+// high-confidence CTIRecord, instead of requiring keyword extraction over
+// free text.
+func TestSTIXBundleParserLinksRelatedObjects(t *testing.T) {
+	bundle := []byte(`{
+		"type": "bundle",
+		"id": "bundle--test",
+		"objects": [
+			{
+				"type": "intrusion-set",
+				"id": "intrusion-set--apt29",
+				"name": "APT29"
+			},
+			{
+				"type": "attack-pattern",
+				"id": "attack-pattern--t1059",
+				"name": "Command and Scripting Interpreter",
+				"kill_chain_phases": [{"kill_chain_name": "mitre-attack", "phase_name": "execution"}],
+				"external_references": [{"source_name": "mitre-attack", "external_id": "T1059"}]
+			},
+			{
+				"type": "indicator",
+				"id": "indicator--evil-ip",
+				"pattern": "[ipv4-addr:value = '198.51.100.7']",
+				"pattern_type": "stix"
+			},
+			{
+				"type": "relationship",
+				"id": "relationship--1",
+				"relationship_type": "uses",
+				"source_ref": "intrusion-set--apt29",
+				"target_ref": "attack-pattern--t1059"
+			},
+			{
+				"type": "relationship",
+				"id": "relationship--2",
+				"relationship_type": "indicates",
+				"source_ref": "indicator--evil-ip",
+				"target_ref": "intrusion-set--apt29"
+			}
+		]
+	}`)
+
+	parser := threatdnacore.NewSTIXBundleParser()
+	records, err := parser.ParseBundle(bundle)
+	if err != nil {
+		t.Fatalf("ParseBundle failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 CTIRecord, got %d", len(records))
+	}
+
+	record := records[0]
+	if record.Actor != "APT29" {
+		t.Errorf("Expected Actor APT29, got %q", record.Actor)
+	}
+
+	if len(record.TTPs) != 1 || record.TTPs[0].TechniqueID != "T1059" {
+		t.Fatalf("Expected a single T1059 TTP, got %+v", record.TTPs)
+	}
+	if record.TTPs[0].Confidence != 1.0 {
+		t.Errorf("Expected STIX-derived TTP confidence 1.0, got %f", record.TTPs[0].Confidence)
+	}
+	if record.TTPs[0].Tactic != "execution" {
+		t.Errorf("Expected tactic 'execution', got %q", record.TTPs[0].Tactic)
+	}
+
+	if len(record.IOCs) != 1 || record.IOCs[0].Type != "ip" || record.IOCs[0].Value != "198.51.100.7" {
+		t.Fatalf("Expected a single ip IOC 198.51.100.7, got %+v", record.IOCs)
+	}
+}
+
+// TestSTIXBundleParserWithoutSubjectStillSurfacesIndicators verifies that a
+// bundle with no intrusion-set/campaign still produces one CTIRecord for
+// its indicators, rather than dropping them.
+func TestSTIXBundleParserWithoutSubjectStillSurfacesIndicators(t *testing.T) {
+	bundle := []byte(`{
+		"type": "bundle",
+		"id": "bundle--test2",
+		"objects": [
+			{
+				"type": "indicator",
+				"id": "indicator--evil-domain",
+				"pattern": "[domain-name:value = 'evil.example.com']",
+				"pattern_type": "stix"
+			}
+		]
+	}`)
+
+	parser := threatdnacore.NewSTIXBundleParser()
+	records, err := parser.ParseBundle(bundle)
+	if err != nil {
+		t.Fatalf("ParseBundle failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 fallback CTIRecord, got %d", len(records))
+	}
+	if records[0].Actor != "" {
+		t.Errorf("Expected no attributed actor, got %q", records[0].Actor)
+	}
+}